@@ -12,6 +12,7 @@ import (
 type addCommand struct {
 	alias       string
 	description string
+	concurrent  bool
 	baseCmd     *cobra.Command
 }
 
@@ -20,13 +21,38 @@ func NewAddCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	addCmd := &cobra.Command{
 		Use:   "add",
 		Short: "Adds a new activity",
-		Long:  "Registers a new activity to be tracked. You can also add an alias to the activity. Case is ignored for the activity name.",
-		Args:  cobra.ExactArgs(1),
+		Long: `
+			Registers a new activity to be tracked. You can also add an alias to the activity. Case is ignored for the activity name.
+			Sub-activities are named after their parent, separated by '/', for example: $ tt add work/backend/reviews
+			Parents that do not exist yet are added as well.
+			A concurrent activity (--concurrent), like an on-call shift, can be tracked at the same time as other activities.
+		`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
 			alias := cmd.Flag("alias").Value.String()
 			description := cmd.Flag("desc").Value.String()
-			activity := core.Activity{Name: args[0], Alias: alias, Description: description}
+			concurrent, _ := cmd.Flags().GetBool("concurrent")
+			activity := core.Activity{Name: args[0], Alias: alias, Description: description, Concurrent: concurrent}
+			errName := activity.ValidateName()
+			if errName != nil {
+				fmt.Println(errName)
+				os.Exit(1)
+			}
+
+			for _, parentName := range core.AncestorActivityNames(activity.Name) {
+				if _, errFind := activityRepo.Find(parentName); errFind == nil {
+					continue
+				}
+
+				errParent := activityRepo.Add(core.Activity{Name: parentName})
+				if errParent != nil {
+					fmt.Println(errParent)
+					os.Exit(1)
+				}
+				fmt.Printf("Added parent activity %s\n", parentName)
+			}
+
 			errAdd := activityRepo.Add(activity)
 			if errAdd != nil {
 				fmt.Println(errAdd)
@@ -37,6 +63,7 @@ func NewAddCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	add := addCommand{}
 	addCmd.Flags().StringVarP(&add.alias, "alias", "a", "", "Activity alias")
 	addCmd.Flags().StringVarP(&add.description, "desc", "d", "", "Activity description")
+	addCmd.Flags().BoolVar(&add.concurrent, "concurrent", false, "Allow tracking the activity at the same time as other activities")
 	add.baseCmd = addCmd
 	return addCmd
 }
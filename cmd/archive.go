@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// NewArchiveCommand archives an activity
+func NewArchiveCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	archiveCmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archives an activity",
+		Long: `
+			Archives an activity, along with its sub-activities. Archived activities are hidden from the activities list
+			and cannot be started, but their sessions keep appearing in reports. Restore them with the 'unarchive' command.
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			err := activityRepo.Archive(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Activity %s archived\n", args[0])
+		},
+	}
+	return archiveCmd
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+)
+
+// atFlagUsage describes the values accepted by the --at flag
+const atFlagUsage = "When it happened instead of now: a time of day (17:45), a duration ago (\"10 minutes ago\") or a date and time (2026-10-17 17:45)"
+
+// parseAtFlag returns the instant given with the --at flag, or nil if it was not given
+func parseAtFlag(value string, configuration config.Config) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	instant, err := core.ParseInstantArgument(value, time.Now().In(configuration.Location))
+	if err != nil {
+		return nil, err
+	}
+	return &instant, nil
+}
+
+// parsePlannedEndFlags returns the instant a session starting at the given one is planned to end at, given either
+// as a duration with the --for flag or as an instant with the --until flag, or nil if neither was given
+func parsePlannedEndFlags(forValue string, untilValue string, startedAt time.Time) (*time.Time, error) {
+	switch {
+	case forValue != "" && untilValue != "":
+		return nil, errors.New("give either how long the session lasts (--for) or when it ends (--until), not both")
+	case forValue != "":
+		duration, err := time.ParseDuration(forValue)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration '%s', expected a duration like 45m or 1h30m", forValue)
+		}
+		end := startedAt.Add(duration)
+		return &end, nil
+	case untilValue != "":
+		end, err := core.ParseUntilArgument(untilValue, startedAt)
+		if err != nil {
+			return nil, err
+		}
+		return &end, nil
+	}
+	return nil, nil
+}
@@ -3,30 +3,151 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/luispcosta/go-tt/config"
 	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
 	"github.com/spf13/cobra"
 )
 
+type currentCommand struct {
+	format         string
+	idle           string
+	durationFormat string
+	baseCmd        *cobra.Command
+}
+
+// currentStatus holds the fields of the session being tracked given to the template of the current command
+type currentStatus struct {
+	Name        string
+	Alias       string
+	Description string
+	Start       time.Time
+	Paused      bool
+	Elapsed     string
+	Today       string
+	Left        string
+}
+
 // NewCurrentCommand displays the current running activity
-func NewCurrentCommand(activityRepo core.ActivityRepository) *cobra.Command {
+func NewCurrentCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
 	currentCmd := &cobra.Command{
 		Use:   "current",
-		Short: "Displays the current running activity, if any",
-		Args:  cobra.NoArgs,
+		Short: "Displays the current running activities, if any",
+		Long: `
+			Displays the activities being tracked, with their elapsed time.
+
+			For shell prompts and status bars, the activity started last can be printed with a Go template (--format),
+			with the fields .Name, .Alias, .Description, .Start, .Paused, .Elapsed, .Today (the time spent on the
+			activity today) and .Left (the time left until its planned end, if any), for example:
+			$ tt current --format '{{.Name}} {{.Elapsed}}{{if .Paused}} (paused){{end}}' --durationFormat m
+			Durations are printed in the format given with --durationFormat, as in reports, and .Start is a time that
+			can be formatted, as in {{.Start.Format "15:04"}}. When no activity is being tracked, the text given with
+			--idle is printed instead, nothing by default. Like every command, it first checks the schema version of the
+			database, with a single query when the schema is up to date, and migrates it if it is older.
+		`,
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
-			activity, err := activityRepo.CurrentlyTrackedActivity()
+			if format := cmd.Flag("format").Value.String(); format != "" {
+				durationFormat := core.ParseDurationFormat(strings.ToLower(cmd.Flag("durationFormat").Value.String()))
+				err := printCurrentStatus(activityRepo, format, cmd.Flag("idle").Value.String(), durationFormat, configuration)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				return
+			}
+
+			sessions, err := activityRepo.CurrentSessions()
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
-			if activity != nil {
-				fmt.Printf("%s (%s)\n", activity.Name, activity.Alias)
-			} else {
+			if len(sessions) == 0 {
 				fmt.Println("Not currently tracking any activity")
+				return
+			}
+			now := time.Now()
+			for _, session := range sessions {
+				elapsed := utils.SecondsToHuman(int(session.Elapsed(now).Seconds()))
+				remaining := ""
+				if left, ok := session.Remaining(now); ok {
+					remaining = fmt.Sprintf(", %sleft", utils.SecondsToHuman(int(left.Seconds())))
+				}
+				if session.IsPaused() {
+					fmt.Printf("%s (%s) - paused, %selapsed%s\n", session.Activity.Name, session.Activity.Alias, elapsed, remaining)
+				} else {
+					fmt.Printf("%s (%s) - %selapsed%s\n", session.Activity.Name, session.Activity.Alias, elapsed, remaining)
+				}
 			}
 		},
 	}
+	current := currentCommand{}
+	currentCmd.Flags().StringVar(&current.format, "format", "", "Go template the activity started last is printed with, as in '{{.Name}} {{.Elapsed}}'")
+	currentCmd.Flags().StringVar(&current.idle, "idle", "", "Text printed with --format when no activity is being tracked")
+	currentCmd.Flags().StringVarP(&current.durationFormat, "durationFormat", "d", "auto", "Duration format of the template fields")
+	current.baseCmd = currentCmd
 	return currentCmd
 }
+
+// printCurrentStatus prints the session started last with the template, or the idle text if there is none.
+// The sessions are read at once without changing them, so that this can run on every prompt.
+func printCurrentStatus(activityRepo core.ActivityRepository, format string, idle string, durationFormat core.DurationFormat, configuration config.Config) error {
+	tmpl, err := template.New("current").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+
+	now := time.Now().In(configuration.Location)
+	today, err := parsePeriodArguments(nil, configuration)
+	if err != nil {
+		return err
+	}
+
+	logs, err := activityRepo.CurrentActivityLogs(today.Interval().Start)
+	if err != nil {
+		return err
+	}
+
+	status := core.NewSessionStatus(logs, today, now)
+	if !status.Tracking {
+		if idle != "" {
+			fmt.Println(idle)
+		}
+		return nil
+	}
+
+	data := newCurrentStatus(status, durationFormat, configuration)
+	var output strings.Builder
+	err = tmpl.Execute(&output, data)
+	if err != nil {
+		return fmt.Errorf("invalid format: %w", err)
+	}
+	fmt.Println(output.String())
+	return nil
+}
+
+// newCurrentStatus returns the fields of the session given to templates, with durations in the given format
+func newCurrentStatus(status core.SessionStatus, durationFormat core.DurationFormat, configuration config.Config) currentStatus {
+	formatDuration := func(duration time.Duration) string {
+		return strings.TrimSpace(durationFormat.Format(int(duration.Seconds())))
+	}
+
+	data := currentStatus{
+		Name:        status.Activity.Name,
+		Alias:       status.Activity.Alias,
+		Description: status.Activity.Description,
+		Start:       status.Start.In(configuration.Location),
+		Paused:      status.Paused,
+		Elapsed:     formatDuration(status.Elapsed),
+		Today:       formatDuration(status.Today),
+	}
+	if status.Remaining > 0 {
+		data.Left = formatDuration(status.Remaining)
+	}
+	return data
+}
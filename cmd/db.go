@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/persistence"
+	"github.com/spf13/cobra"
+)
+
+type dbMigrateCommand struct {
+	down    int
+	baseCmd *cobra.Command
+}
+
+// NewDbCommand groups the commands that manage the database schema
+func NewDbCommand(repo *persistence.SqliteRepository) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manages the database schema",
+		Long:  "Inspects and changes the version of the database schema. Pending migrations are applied automatically every time the application runs, except for these commands.",
+	}
+	dbCmd.AddCommand(newDbVersionCommand(repo))
+	dbCmd.AddCommand(newDbMigrateCommand(repo))
+	return dbCmd
+}
+
+func newDbVersionCommand(repo *persistence.SqliteRepository) *cobra.Command {
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Displays the database schema version",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			version, dirty, err := repo.SchemaVersion()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			latest, err := persistence.LatestSchemaVersion()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Schema version: %d (latest supported: %d)\n", version, latest)
+			if dirty {
+				fmt.Println("The schema is dirty: the last migration failed and must be fixed manually")
+			}
+		},
+	}
+	return versionCmd
+}
+
+func newDbMigrateCommand(repo *persistence.SqliteRepository) *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrates the database schema",
+		Long: `
+			Applies all the pending migrations to the database schema.
+			With --down N, the last N applied migrations are reverted instead. Reverting migrations may delete your data.
+		`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			down := cmd.Flag("down")
+			if !down.Changed {
+				err := repo.Migrate()
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				fmt.Println("Done")
+				return
+			}
+
+			steps, err := cmd.Flags().GetInt("down")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if AllowedToContinue() {
+				errDown := repo.MigrateDown(steps)
+				if errDown != nil {
+					fmt.Println(errDown)
+					os.Exit(1)
+				}
+				fmt.Println("Done")
+			}
+		},
+	}
+	migrate := dbMigrateCommand{}
+	migrateCmd.Flags().IntVarP(&migrate.down, "down", "d", 0, "Number of migrations to revert")
+	migrate.baseCmd = migrateCmd
+	return migrateCmd
+}
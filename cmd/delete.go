@@ -9,17 +9,48 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type deleteCommand struct {
+	deleteLogs bool
+	moveLogsTo string
+	baseCmd    *cobra.Command
+}
+
 // NewDeleteCommand deletes an activity registered from the system
 func NewDeleteCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	deleteCmd := &cobra.Command{
 		Use:   "del",
 		Short: "Deletes an activity",
-		Long:  "Deletes an activity, if it exists. The argument can be either the activity name or alias",
-		Args:  cobra.ExactArgs(1),
+		Long: `
+			Deletes an activity, if it exists. The argument can be either the activity name or alias.
+			If the activity has tracked sessions, you must choose what happens to them: either delete them as well (--delete-logs),
+			or move them to another activity (--move-logs-to <ACTIVITY>). To keep the history of an activity you no longer use,
+			archive it with the 'archive' command instead.
+		`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
 			activityNameOrAlias := args[0]
-			errDelete := activityRepo.Delete(activityNameOrAlias)
+			opts := core.DeleteOptions{}
+			deleteLogs, _ := cmd.Flags().GetBool("delete-logs")
+			if deleteLogs {
+				fmt.Println("The sessions of the activity will be deleted.")
+				if !AllowedToContinue() {
+					return
+				}
+				opts.DeleteLogs = true
+			}
+
+			moveLogsTo := cmd.Flag("move-logs-to").Value.String()
+			if moveLogsTo != "" {
+				target, err := activityRepo.Find(moveLogsTo)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				opts.MoveLogsTo = target
+			}
+
+			errDelete := activityRepo.Delete(activityNameOrAlias, opts)
 			if errDelete != nil {
 				fmt.Println(errDelete)
 				os.Exit(1)
@@ -28,5 +59,9 @@ func NewDeleteCommand(activityRepo core.ActivityRepository) *cobra.Command {
 			}
 		},
 	}
+	del := deleteCommand{}
+	deleteCmd.Flags().BoolVar(&del.deleteLogs, "delete-logs", false, "Delete the sessions of the activity")
+	deleteCmd.Flags().StringVar(&del.moveLogsTo, "move-logs-to", "", "Name or alias of the activity the sessions are moved to")
+	del.baseCmd = deleteCmd
 	return deleteCmd
 }
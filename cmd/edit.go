@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+const editErrorPrefix = "# ERROR: "
+
+// NewEditCommand edits the sessions of a period as text, in the user's editor
+func NewEditCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit",
+		Short: "Edits the sessions of a period in your text editor",
+		Long: fmt.Sprintf(`
+			Opens the sessions of a period in your text editor ($EDITOR, or vi if it is not set), one line per session:
+
+				id | start | stop | activity | note
+
+			Change a line to fix its session, delete a line to delete its session, or add a line with 'new' as id to add a session.
+			Once the editor is closed, all the changes are saved together. If they cannot be read or make sessions overlap,
+			the editor is opened again with the error, so it can be fixed. Close it without changes to give up.
+
+			By default, the sessions of the current day are edited. %s
+		`, periodArgumentsHelp),
+		Args: cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			period, err := parsePeriodArguments(args, configuration)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			sessions, err := activityRepo.SessionsForPeriod(period, nil)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			original := core.FormatSessionsText(sessions, configuration.Location)
+			text := original
+			for {
+				edited, err := editText(text)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				if edited == original {
+					fmt.Println("No changes were made")
+					return
+				}
+
+				if edited == text {
+					fmt.Println("Edit aborted, no changes were saved")
+					os.Exit(1)
+				}
+
+				changes, err := core.ParseSessionsText(edited, sessions, configuration.Location, activityRepo.Find)
+				if err == nil && !changes.IsEmpty() {
+					err = activityRepo.ApplySessionChanges(changes)
+				}
+
+				if err != nil {
+					text = withEditError(edited, err)
+					continue
+				}
+
+				fmt.Printf("%d session(s) added, %d updated and %d deleted\n", len(changes.Inserts), len(changes.Updates), len(changes.Deletes))
+				return
+			}
+		},
+	}
+}
+
+// editText opens the text in the user's editor and returns it once the editor is closed
+func editText(text string) (string, error) {
+	file, err := ioutil.TempFile("", "tt-edit-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(text)
+	if err == nil {
+		err = file.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	editorArgs := strings.Fields(editor)
+	editorCmd := exec.Command(editorArgs[0], append(editorArgs[1:], file.Name())...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	err = editorCmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("could not run the editor '%s': %v", editor, err)
+	}
+
+	edited, err := ioutil.ReadFile(file.Name())
+	return string(edited), err
+}
+
+// withEditError replaces the error reported at the top of the text by the given one
+func withEditError(text string, err error) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, editErrorPrefix) {
+			lines = append(lines, line)
+		}
+	}
+	return editErrorPrefix + err.Error() + "\n" + strings.Join(lines, "\n")
+}
@@ -14,7 +14,7 @@ func NewInitCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	deleteCmd := &cobra.Command{
 		Use:   "init",
 		Short: "Inits the application",
-		Long:  "Setup the application, by creating the necessary data.",
+		Long:  "Setup the application, by creating the necessary data and applying the database schema migrations.",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			config := config.NewConfig()
@@ -27,6 +27,12 @@ func NewInitCommand(activityRepo core.ActivityRepository) *cobra.Command {
 					os.Exit(1)
 				}
 			}
+
+			errInit := activityRepo.Initialize(config)
+			if errInit != nil {
+				fmt.Println(errInit.Error())
+				os.Exit(1)
+			}
 		},
 	}
 	return deleteCmd
@@ -8,12 +8,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+type listCommand struct {
+	all     bool
+	baseCmd *cobra.Command
+}
+
 // NewListCommand registers the list activity command
 func NewListCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "Lists all activities",
-		Long:  "Lists all the current registered activities in the system",
+		Long:  "Lists all the current registered activities in the system. Archived activities are only listed with --all.",
 		Args:  cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
@@ -23,10 +28,17 @@ func NewListCommand(activityRepo core.ActivityRepository) *cobra.Command {
 				os.Exit(1)
 			}
 
+			all, _ := cmd.Flags().GetBool("all")
 			for _, act := range activities {
+				if act.Archived && !all {
+					continue
+				}
 				fmt.Println(act.ToPrintableString())
 			}
 		},
 	}
+	list := listCommand{}
+	listCmd.Flags().BoolVarP(&list.all, "all", "a", false, "Also list archived activities")
+	list.baseCmd = listCmd
 	return listCmd
 }
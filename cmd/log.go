@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+type logCommand struct {
+	date      string
+	from      string
+	to        string
+	duration  time.Duration
+	endingNow bool
+	note      string
+	baseCmd   *cobra.Command
+}
+
+// NewLogCommand records a session that was not tracked live
+func NewLogCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	log := logCommand{}
+	logCmd := &cobra.Command{
+		Use:   "log",
+		Short: "Records a session you forgot to track",
+		Long: `
+			Records a finished session of an activity, for time you forgot to track with the 'start' and 'stop' commands.
+			The session is given by two of a start time (--from), a stop time (--to) and a duration (--duration), for example:
+			$ tt log coding --from 09:00 --to 10:30
+			$ tt log coding --from 09:00 --duration 1h30m --date 2026-10-17
+			$ tt log coding --duration 1h30m --ending-now
+
+			Times are times of day, on the given day (--date) or today. A stop time before the start time is on the next day.
+			The day is given as in periods: a date (YYYY-MM-DD, or in the formats of the "date_formats" setting), yesterday,
+			a weekday ("last monday") or days ago ("3 days ago").
+			The session cannot overlap other sessions. Like with the 'start' command, it can have a note and tags, as in:
+			$ tt log coding +billable --from 09:00 --to 10:30 -m "fixed the parser"
+		`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			activity, err := activityRepo.Find(args[0])
+			if err != nil {
+				fmt.Printf("Could not log activity. Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			tags, err := parseTagArguments(args[1:])
+			if err != nil {
+				fmt.Printf("Could not log activity. Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			entry := core.SessionEntry{Date: log.date, From: log.from, To: log.to, Duration: log.duration, EndingNow: log.endingNow}
+			interval, err := entry.Interval(newPeriodParser(configuration))
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			err = activityRepo.LogSession(*activity, interval, core.StartOptions{Note: log.note, Tags: tags})
+			if err != nil {
+				fmt.Printf("Could not log activity. Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			logged := core.ActivityLog{StartedAt: &interval.Start, StoppedAt: &interval.End}
+			fmt.Printf("Logged %s %s\n", activity.Name, logged.Describe())
+		},
+	}
+	logCmd.Flags().StringVar(&log.date, "date", "", "Day of the session, as in 2026-10-17 or yesterday, today by default")
+	logCmd.Flags().StringVar(&log.from, "from", "", "Time of day the session started")
+	logCmd.Flags().StringVar(&log.to, "to", "", "Time of day the session stopped")
+	logCmd.Flags().DurationVar(&log.duration, "duration", 0, "Duration of the session, as in 1h30m")
+	logCmd.Flags().BoolVar(&log.endingNow, "ending-now", false, "The session stops now")
+	logCmd.Flags().StringVarP(&log.note, "note", "m", "", "Note describing the session")
+	log.baseCmd = logCmd
+	return logCmd
+}
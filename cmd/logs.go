@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
+	"github.com/spf13/cobra"
+)
+
+type logsCommand struct {
+	activity string
+	baseCmd  *cobra.Command
+}
+
+type logsEditCommand struct {
+	start    string
+	stop     string
+	activity string
+	baseCmd  *cobra.Command
+}
+
+// NewLogsCommand lists and manages individual sessions
+func NewLogsCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	logs := logsCommand{}
+	logsCmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Lists the sessions of a period",
+		Long: fmt.Sprintf(`
+			Lists the sessions of a period, with their ids, start, stop and duration. By default, the sessions of the current day are listed.
+			%s
+			You can list the sessions of a single activity with --activity <ACTIVITY>.
+
+			Single sessions can be inspected, fixed or deleted by their id, with the 'show', 'edit' and 'rm' sub-commands.
+		`, periodArgumentsHelp),
+		Args: cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			period, err := parsePeriodArguments(args, configuration)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			var activity *core.Activity
+			if logs.activity != "" {
+				activity, err = activityRepo.Find(logs.activity)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			sessions, err := activityRepo.SessionsForPeriod(period, activity)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if len(sessions) == 0 {
+				fmt.Println("No sessions found for this period")
+			}
+
+			now := time.Now()
+			for _, session := range sessions {
+				fmt.Println(sessionSummary(session.In(configuration.Location), now))
+			}
+		},
+	}
+	logsCmd.Flags().StringVarP(&logs.activity, "activity", "a", "", "Only list the sessions of this activity")
+	logs.baseCmd = logsCmd
+
+	logsCmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Shows the details of a session",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			session := findSessionArgument(activityRepo, args[0]).In(configuration.Location)
+			fmt.Println(sessionSummary(session, time.Now()))
+			for _, pause := range session.Pauses {
+				resumed := "not resumed yet"
+				if pause.ResumedAt != nil {
+					resumed = "resumed at " + pause.ResumedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Printf("  Paused at %s, %s\n", pause.PausedAt.Format("2006-01-02 15:04:05"), resumed)
+			}
+			if session.Note != "" {
+				fmt.Printf("  Note: %s\n", session.Note)
+			}
+		},
+	})
+
+	edit := logsEditCommand{}
+	editCmd := &cobra.Command{
+		Use:   "edit",
+		Short: "Fixes the start, the stop or the activity of a session",
+		Long: `
+			Fixes the start (--start), the stop (--stop) or the activity (--activity) of a session, given by its id.
+			Times are either a time of day, on the day the session started or stopped, or a date and a time, for example:
+			$ tt logs edit 12 --start 09:15 --stop "2026-10-18 01:30"
+			The session cannot overlap other sessions, nor stop before it starts.
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			session := findSessionArgument(activityRepo, args[0]).In(configuration.Location)
+			update := core.SessionUpdate{}
+
+			if edit.start != "" {
+				start, err := core.ParseInstant(edit.start, *session.StartedAt)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				update.StartedAt = &start
+			}
+
+			if edit.stop != "" {
+				day := time.Now().In(configuration.Location)
+				if session.StoppedAt != nil {
+					day = *session.StoppedAt
+				}
+				stop, err := core.ParseInstant(edit.stop, day)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				update.StoppedAt = &stop
+			}
+
+			if edit.activity != "" {
+				activity, err := activityRepo.Find(edit.activity)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				update.Activity = activity
+			}
+
+			err := activityRepo.UpdateSession(session.Id, update)
+			if err != nil {
+				fmt.Printf("Could not edit session %d. Error: %s\n", session.Id, err.Error())
+				os.Exit(1)
+			}
+
+			edited := findSessionArgument(activityRepo, args[0]).In(configuration.Location)
+			fmt.Println(sessionSummary(edited, time.Now()))
+		},
+	}
+	editCmd.Flags().StringVar(&edit.start, "start", "", "New start of the session")
+	editCmd.Flags().StringVar(&edit.stop, "stop", "", "New stop of the session")
+	editCmd.Flags().StringVarP(&edit.activity, "activity", "a", "", "New activity of the session")
+	edit.baseCmd = editCmd
+	logsCmd.AddCommand(editCmd)
+
+	rmCmd := &cobra.Command{
+		Use:   "rm",
+		Short: "Deletes a session",
+		Long: `
+			Deletes a session after asking for confirmation, or without asking with --yes, as in scripts.
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			session := findSessionArgument(activityRepo, args[0]).In(configuration.Location)
+			fmt.Println(sessionSummary(session, time.Now()))
+			yes, _ := cmd.Flags().GetBool("yes")
+			if yes || AllowedToContinue() {
+				err := activityRepo.DeleteSession(session.Id)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				fmt.Println("Done")
+			}
+		},
+	}
+	rmCmd.Flags().BoolP("yes", "y", false, "Delete the session without asking for confirmation")
+	logsCmd.AddCommand(rmCmd)
+
+	return logsCmd
+}
+
+// findSessionArgument returns the session whose id is given in the command line
+func findSessionArgument(activityRepo core.ActivityRepository, arg string) core.ActivityLog {
+	logId, err := strconv.Atoi(arg)
+	if err != nil {
+		fmt.Printf("Invalid session id '%s'\n", arg)
+		os.Exit(1)
+	}
+
+	session, err := activityRepo.FindSession(logId)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return *session
+}
+
+// sessionSummary returns a line describing the session: its id, activity, when it took place, its duration and its tags
+func sessionSummary(session core.ActivityLog, now time.Time) string {
+	summary := fmt.Sprintf("%5d  %-20s %s, %s", session.Id, session.Activity.Name, session.Describe(), utils.SecondsToHuman(int(session.Elapsed(now).Seconds())))
+	if session.IsPaused() {
+		summary += "(paused) "
+	}
+	if tags := session.AllTags(); len(tags) > 0 {
+		summary += core.FormatTags(tags)
+	}
+	return summary
+}
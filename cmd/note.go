@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+type noteCommand struct {
+	id      int
+	baseCmd *cobra.Command
+}
+
+// NewNoteCommand amends the note of a session
+func NewNoteCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	noteCmd := &cobra.Command{
+		Use:   "note",
+		Short: "Sets the note of a session",
+		Long: `
+			Replaces the note of a tracking session with the given text. An empty text removes the note.
+			By default, the note of the current session (or of the last one, if no activity is being tracked) is replaced.
+			You can choose another session with its id (--id <ID>).
+		`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			logId, err := cmd.Flags().GetInt("id")
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if !cmd.Flag("id").Changed {
+				session, errLast := activityRepo.LastSession()
+				if errLast != nil {
+					fmt.Println(errLast)
+					os.Exit(1)
+				}
+				if session == nil {
+					fmt.Println("There are no sessions yet, start tracking an activity with the 'start' command")
+					os.Exit(1)
+				}
+				logId = session.Id
+			}
+
+			errNote := activityRepo.SetNote(logId, args[0])
+			if errNote != nil {
+				fmt.Println(errNote)
+				os.Exit(1)
+			}
+		},
+	}
+	note := noteCommand{}
+	noteCmd.Flags().IntVarP(&note.id, "id", "i", 0, "Id of the session")
+	note.baseCmd = noteCmd
+	return noteCmd
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// NewPauseCommand pauses the activity being tracked
+func NewPauseCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	pauseCmd := &cobra.Command{
+		Use:   "pause",
+		Short: "Pauses the current activity",
+		Long:  "Starts a break in the activity being tracked. The time spent in the break is not counted, until the activity is resumed.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			errPause := activityRepo.Pause()
+			if errPause != nil {
+				fmt.Printf("Could not pause activity - error: %s\n", errPause.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	return pauseCmd
+}
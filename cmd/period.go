@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+)
+
+// periodArgumentsHelp describes the arguments of the commands working on a period
+var periodArgumentsHelp = fmt.Sprintf(`The period is either a fixed time frame (%v), a calendar period (2026-W42, 2026-10, 2026-Q3 or 2026),
+			a single day, "since <DAY>", the time until now since a time of day ("since 08:00") or in the last hours ("last 4 hours"),
+			or a first and a last day or calendar period.
+			Calendar weeks start on the day of the "week_start" setting, Monday by default.
+			Days are dates, today, yesterday, a weekday (monday, "last monday") or days ago ("3 days ago", "2 weeks ago"),
+			for example: yesterday, "3 days ago" today or "since 2026-09-01".
+			Dates are given as YYYY-MM-DD, or in the formats of the "date_formats" setting.`, core.AllowedPeriodFixedTimeFrames())
+
+// parsePeriodArguments returns the period given in the command line, relatively to the current day in the
+// configured time zone. Without arguments, the period is the current day.
+func parsePeriodArguments(args []string, configuration config.Config) (core.Period, error) {
+	return newPeriodParser(configuration).Parse(args...)
+}
+
+// newPeriodParser returns a parser of the periods and days given in the command line, relatively to the current
+// instant in the configured time zone
+func newPeriodParser(configuration config.Config) core.PeriodParser {
+	return core.PeriodParser{Now: time.Now().In(configuration.Location), DateFormats: configuration.DateFormats, WeekStart: configuration.WeekStart}
+}
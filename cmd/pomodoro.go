@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// pomodoroBreakActivity is the name of the activity breaks are logged as
+const pomodoroBreakActivity = "break"
+
+type pomodoroCommand struct {
+	work      time.Duration
+	breakTime time.Duration
+	cycles    int
+	logBreaks bool
+	baseCmd   *cobra.Command
+}
+
+// NewPomodoroCommand tracks an activity in work intervals separated by breaks
+func NewPomodoroCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	pomodoroCmd := &cobra.Command{
+		Use:   "pomodoro",
+		Short: "Works on an activity in intervals separated by breaks",
+		Long: `
+			Runs a pomodoro timer in the foreground: the activity is tracked for a work interval (--work), followed by
+			a break (--break), as many times as given (--cycles). A countdown is shown in the terminal, and the bell
+			rings when an interval starts. Each work interval is recorded as a session of the activity, and breaks are
+			recorded as sessions of the 'break' activity with --log-breaks. The session can be tagged as with start:
+			$ tt pomodoro coding +clientA --work 50m --break 10m --cycles 2
+
+			Press Ctrl-C to stop the current interval and leave.
+		`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			activity, err := activityRepo.Find(args[0])
+			if err != nil {
+				fmt.Printf("Could not start activity. Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+			tags, errTags := parseTagArguments(args[1:])
+			if errTags != nil {
+				fmt.Printf("Could not start activity. Error: %s\n", errTags.Error())
+				os.Exit(1)
+			}
+
+			work, _ := cmd.Flags().GetDuration("work")
+			breakTime, _ := cmd.Flags().GetDuration("break")
+			cycles, _ := cmd.Flags().GetInt("cycles")
+			if work <= 0 || breakTime <= 0 || cycles <= 0 {
+				fmt.Println("The work and break durations, and the number of cycles, must be positive")
+				os.Exit(1)
+			}
+
+			var breakActivity *core.Activity
+			logBreaks, _ := cmd.Flags().GetBool("log-breaks")
+			if logBreaks {
+				breakActivity, err = findOrAddBreakActivity(activityRepo)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			interrupted := make(chan os.Signal, 1)
+			signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+			defer signal.Stop(interrupted)
+
+			for cycle := 1; cycle <= cycles; cycle++ {
+				label := fmt.Sprintf("Working on %s (%d/%d)", activity.Name, cycle, cycles)
+				opts := core.StartOptions{Tags: tags}
+				if !runPomodoroInterval(activityRepo, activity, opts, work, label, interrupted, configuration) {
+					return
+				}
+
+				if cycle == cycles {
+					break
+				}
+
+				label = fmt.Sprintf("Break (%d/%d)", cycle, cycles-1)
+				if !runPomodoroInterval(activityRepo, breakActivity, core.StartOptions{}, breakTime, label, interrupted, configuration) {
+					return
+				}
+			}
+
+			fmt.Printf("\aDone: %d work intervals of %v on %s\n", cycles, work, activity.Name)
+		},
+	}
+	pomodoro := pomodoroCommand{}
+	pomodoroCmd.Flags().DurationVar(&pomodoro.work, "work", 25*time.Minute, "How long each work interval lasts")
+	pomodoroCmd.Flags().DurationVar(&pomodoro.breakTime, "break", 5*time.Minute, "How long each break lasts")
+	pomodoroCmd.Flags().IntVar(&pomodoro.cycles, "cycles", 4, "How many work intervals to run")
+	pomodoroCmd.Flags().BoolVar(&pomodoro.logBreaks, "log-breaks", false, "Record the breaks as sessions of the 'break' activity")
+	pomodoro.baseCmd = pomodoroCmd
+	return pomodoroCmd
+}
+
+// findOrAddBreakActivity returns the activity breaks are logged as, adding it if it does not exist yet
+func findOrAddBreakActivity(activityRepo core.ActivityRepository) (*core.Activity, error) {
+	if activity, err := activityRepo.Find(pomodoroBreakActivity); err == nil {
+		return activity, nil
+	}
+
+	err := activityRepo.Add(core.Activity{Name: pomodoroBreakActivity})
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Added activity %s\n", pomodoroBreakActivity)
+	return activityRepo.Find(pomodoroBreakActivity)
+}
+
+// runPomodoroInterval shows a countdown for the duration, tracking the activity meanwhile unless it is nil. The session
+// is planned to end with the interval, so it stops at the right time even if the process dies. It returns false if
+// the interval was interrupted, in which case the session is stopped at once.
+func runPomodoroInterval(activityRepo core.ActivityRepository, activity *core.Activity, opts core.StartOptions, duration time.Duration, label string, interrupted <-chan os.Signal, configuration config.Config) bool {
+	end := time.Now().In(configuration.Location).Add(duration)
+	if activity != nil {
+		opts.Until = &end
+		err := activityRepo.Start(*activity, opts)
+		if err != nil {
+			fmt.Printf("Could not start activity with name %s - error: %s\n", activity.Name, err.Error())
+			os.Exit(1)
+		}
+	}
+
+	fmt.Print("\a")
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		fmt.Printf("\r%s - %s left ", label, formatCountdown(time.Until(end)))
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			fmt.Printf("\r%s - done    \n", label)
+			return true
+		case <-interrupted:
+			fmt.Println()
+			if activity != nil {
+				err := activityRepo.Stop(*activity, core.StopOptions{})
+				if err != nil {
+					fmt.Printf("Could not stop activity %s - error: %s\n", activity.Name, err.Error())
+					os.Exit(1)
+				}
+				fmt.Printf("Stopped '%s'\n", activity.Name)
+			}
+			return false
+		}
+	}
+}
+
+// formatCountdown returns the time left as a clock, as in 24:59 or 1:04:59
+func formatCountdown(left time.Duration) string {
+	seconds := int((left + time.Second - 1) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/luispcosta/go-tt/config"
 	"github.com/luispcosta/go-tt/core"
 	"github.com/luispcosta/go-tt/reporter"
 	"github.com/spf13/cobra"
@@ -14,22 +15,27 @@ type reportCmd struct {
 	baseCmd        *cobra.Command
 	format         string
 	durationFormat string
+	tags           []string
+	excludedTags   []string
+	tagTotals      bool
+	depth          int
+	since          string
+	overlap        string
 }
 
 // NewReportCommand creates ativities reports
-func NewReportCommand(activityRepo core.ActivityRepository) *cobra.Command {
+func NewReportCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	report := reportCmd{}
 	reportCommand := &cobra.Command{
 		Use:   "report",
 		Short: "Creates an activity report over a time period",
 		Long: fmt.Sprintf(`
 			Generates a report that presents information about all the activities you performed in the required period.
-			This command accepts 1 or 2 arguments.
-
-			If only 1 argument is provided, than it is assumed that the user wants a report in a fixed time frame. This argument represents
-			that time frame. Accepted values are: %v. All time frames are relative to the current day.
+			This command accepts 1 or 2 arguments. %s
+			All periods are relative to the current day, in the configured time zone.
 
-			If two arguments are passed, they are used to construct a specific time frame period.
-			For example: $ go-tt report '2020-10-10' '2020-10-20'
+			Instead of arguments, the report can cover the time since a given time of day until now with --since <TIME>,
+			for example --since 08:00. Sessions only partially inside such a period are cut to the part inside it.
 
 			You can provide an additional flag (-f <FORMAT> or --format <FORMAT>) to specify the report format. The default format is printing
 			the report to STDOUT. Allowed values are: %v
@@ -37,22 +43,33 @@ func NewReportCommand(activityRepo core.ActivityRepository) *cobra.Command {
 			You can also provide an additional flag (-d <DURATION_FORMAT> or --durationFormat <DURATION_FORMAT>) to specify
 			how each activity duration is printed. The default duration format is 'auto', which prints durations to a human friendly format.
 			Example, an activity duration of 25204 seconds will be printed as "7 hours 0 minute 4 seconds".
-			Accepted values for this flag are 'h' (human), 's' (seconds), 'm' (minutes) and 'r' (hours).
-		`, core.AllowedPeriodFixedTimeFrames(), reporter.AllowedFormatsCollection()),
-		Args: cobra.RangeArgs(1, 2),
+			Accepted values for this flag are 'h' (human), 's' (seconds), 'm' (minutes), 'r' (hours) and 'c' (clock, as in 1:05).
+
+			Sessions can be filtered by their tags, or the tags of their activities: --tag <TAG> keeps only the sessions
+			with the tag, and --exclude-tag <TAG> leaves out the sessions with the tag. Both flags can be repeated, for example:
+			$ go-tt report week --tag billable --exclude-tag personal
+			The total time spent per tag can be added to the report with --tag-totals. In the csv and json formats, they
+			are written to a file of their own, named after the report with a "_tags" suffix.
+
+			Activities are reported in a tree, where the time of each activity includes the time of its sub-activities,
+			along with the time spent on the activity itself. The tree can be collapsed to a given depth with --depth <N>,
+			for example --depth 1 only reports the top level activities.
+		`, periodArgumentsHelp, reporter.AllowedFormatsCollection()),
+		Args: cobra.RangeArgs(0, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
-			var period core.Period
-			if len(args) == 2 {
-				parsedPeriod, errPeriod := core.PeriodFromDateStrings(args[0], args[1])
-
-				if errPeriod != nil {
-					fmt.Println(errPeriod)
+			if report.since != "" {
+				if len(args) > 0 {
+					fmt.Println("A period cannot be given along with --since")
 					os.Exit(1)
 				}
-				period = parsedPeriod
-			} else {
-				period = core.PeriodFromKeyWord(args[0])
+				args = []string{"since " + report.since}
+			}
+
+			period, errPeriod := parsePeriodArguments(args, configuration)
+			if errPeriod != nil {
+				fmt.Println(errPeriod)
+				os.Exit(1)
 			}
 
 			format := strings.ToLower(cmd.Flag("format").Value.String())
@@ -71,6 +88,35 @@ func NewReportCommand(activityRepo core.ActivityRepository) *cobra.Command {
 			}
 			reporter.SetDurationFormat(durationFormat)
 
+			tags, errTags := core.ParseTags(report.tags)
+			if errTags != nil {
+				fmt.Println(errTags)
+				os.Exit(1)
+			}
+			excludedTags, errTags := core.ParseTags(report.excludedTags)
+			if errTags != nil {
+				fmt.Println(errTags)
+				os.Exit(1)
+			}
+			overlapValue := report.overlap
+			if overlapValue == "" {
+				overlapValue = configuration.Overlap
+			}
+			overlap, errOverlap := core.ParseOverlapMode(strings.ToLower(overlapValue))
+			if errOverlap != nil {
+				fmt.Println(errOverlap)
+				os.Exit(1)
+			}
+			reporter.SetFilter(core.LogFilter{Tags: tags, ExcludedTags: excludedTags, Overlap: overlap})
+			reporter.SetTagTotals(report.tagTotals)
+
+			if report.depth < 0 {
+				fmt.Println("The depth of the report cannot be negative")
+				os.Exit(1)
+			}
+			reporter.SetDepth(report.depth)
+			reporter.SetMaxSession(configuration.MaxSession)
+
 			err := reporter.ProduceReport()
 			if err != nil {
 				fmt.Println(err)
@@ -79,7 +125,12 @@ func NewReportCommand(activityRepo core.ActivityRepository) *cobra.Command {
 		},
 	}
 
-	report := reportCmd{}
+	reportCommand.Flags().StringArrayVar(&report.tags, "tag", []string{}, "Only report sessions with this tag")
+	reportCommand.Flags().StringArrayVar(&report.excludedTags, "exclude-tag", []string{}, "Leave out sessions with this tag")
+	reportCommand.Flags().BoolVar(&report.tagTotals, "tag-totals", false, "Add the total time per tag to the report")
+	reportCommand.Flags().StringVar(&report.overlap, "overlap", "", "How time tracked in several sessions at once is counted: full, in each of them, or split between them. Defaults to the overlap setting")
+	reportCommand.Flags().IntVar(&report.depth, "depth", 0, "Depth at which the tree of activities is collapsed, 0 for the whole tree")
+	reportCommand.Flags().StringVar(&report.since, "since", "", "Report the time since this time of day, as in 08:00, until now")
 	reportCommand.Flags().StringVarP(&report.format, "format", "f", "cli", "Report format")
 	reportCommand.Flags().StringVarP(&report.durationFormat, "durationFormat", "d", "auto", "Duration format")
 	report.baseCmd = reportCommand
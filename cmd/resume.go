@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// NewResumeCommand resumes the paused activity
+func NewResumeCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	resumeCmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Resumes the paused activity",
+		Long:  "Ends the break in the activity being tracked, and starts counting its time again",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			errResume := activityRepo.Resume()
+			if errResume != nil {
+				fmt.Printf("Could not resume activity - error: %s\n", errResume.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	return resumeCmd
+}
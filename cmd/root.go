@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -33,7 +34,11 @@ func AllowedToContinue() bool {
 	fmt.Printf("Do you want to continue with this operation? [y|n]: ")
 	_, err := fmt.Scanln(&input)
 	if err != nil {
-		panic(err)
+		// Nothing can be read when the input is closed or not a terminal, which is taken as a no
+		if err == io.EOF {
+			fmt.Println()
+		}
+		return false
 	}
 	input = strings.ToLower(input)
 	if input == "y" {
@@ -42,9 +47,25 @@ func AllowedToContinue() bool {
 	return false
 }
 
+// topLevelCommand returns the command right under the root command that the given command belongs to,
+// as "db" for "db migrate"
+func topLevelCommand(cmd *cobra.Command) *cobra.Command {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd
+}
+
 // Execute executes the root commmand.
 func Execute() {
 	configuration := config.NewConfig()
+	errSettings := configuration.LoadSettings()
+
+	if errSettings != nil {
+		fmt.Println(errSettings)
+		os.Exit(1)
+	}
+
 	repo, err := persistence.NewSqliteRepository()
 
 	if err != nil {
@@ -52,23 +73,46 @@ func Execute() {
 		os.Exit(1)
 	}
 
-	errorInitRepo := repo.Initialize(configuration)
-
-	if errorInitRepo != nil {
-		fmt.Printf("Error initializing mongo with error: %s", errorInitRepo.Error())
-		os.Exit(1)
+	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		checkForgottenSession(cmd, repo, configuration)
 	}
 
 	rootCmd.AddCommand(NewInitCommand(repo))
 	rootCmd.AddCommand(NewAddCommand(repo))
 	rootCmd.AddCommand(NewListCommand(repo))
 	rootCmd.AddCommand(NewDeleteCommand(repo))
-	rootCmd.AddCommand(NewStartCommand(repo))
-	rootCmd.AddCommand(NewStopCommand(repo))
-	rootCmd.AddCommand(NewReportCommand(repo))
+	rootCmd.AddCommand(NewArchiveCommand(repo))
+	rootCmd.AddCommand(NewUnarchiveCommand(repo))
+	rootCmd.AddCommand(NewStartCommand(repo, configuration))
+	rootCmd.AddCommand(NewStopCommand(repo, configuration))
+	rootCmd.AddCommand(NewSwitchCommand(repo, configuration))
+	rootCmd.AddCommand(NewPomodoroCommand(repo, configuration))
+	rootCmd.AddCommand(NewLogCommand(repo, configuration))
+	rootCmd.AddCommand(NewLogsCommand(repo, configuration))
+	rootCmd.AddCommand(NewEditCommand(repo, configuration))
+	rootCmd.AddCommand(NewPauseCommand(repo))
+	rootCmd.AddCommand(NewResumeCommand(repo))
+	rootCmd.AddCommand(NewNoteCommand(repo))
+	rootCmd.AddCommand(NewTagCommand(repo))
+	rootCmd.AddCommand(NewReportCommand(repo, configuration))
 	rootCmd.AddCommand(NewUpdateCommand((repo)))
-	rootCmd.AddCommand(NewCurrentCommand((repo)))
-	rootCmd.AddCommand(NewWipeCommand((repo)))
+	rootCmd.AddCommand(NewCurrentCommand(repo, configuration))
+	rootCmd.AddCommand(NewWatchCommand(repo, configuration))
+	rootCmd.AddCommand(NewStatusCommand(repo, configuration))
+	rootCmd.AddCommand(NewWipeCommand(repo, configuration))
+	rootCmd.AddCommand(NewDbCommand(repo))
+
+	// The db commands manage the schema themselves: migrating it first would undo what they do
+	if command, _, errFind := rootCmd.Find(os.Args[1:]); errFind == nil {
+		configuration.SkipMigrations = topLevelCommand(command).Name() == "db"
+	}
+
+	errorInitRepo := repo.Initialize(configuration)
+
+	if errorInitRepo != nil {
+		fmt.Printf("Error initializing the database: %s\n", errorInitRepo.Error())
+		os.Exit(1)
+	}
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
+	"github.com/spf13/cobra"
+)
+
+// passiveCommands are the commands that are not counted as a use of the application, as they are usually
+// run by other programs, such as shell prompts and status bars, and not by the user. To answer fast,
+// they do not check for forgotten sessions either.
+var passiveCommands = map[string]bool{"current": true, "status": true}
+
+// safeguardSkippedCommands are the commands that do not check for a forgotten session, along with their subcommands
+var safeguardSkippedCommands = map[string]bool{"init": true, "stop": true, "db": true, "help": true}
+
+// checkForgottenSession warns when a session being tracked has been running for longer than the configured
+// maximum, and offers to stop it at an earlier time when the user can answer.
+func checkForgottenSession(cmd *cobra.Command, activityRepo core.ActivityRepository, configuration config.Config) {
+	name := topLevelCommand(cmd).Name()
+	if !configuration.AlreadySetup() || passiveCommands[name] {
+		return
+	}
+
+	now := time.Now().In(configuration.Location)
+	lastInvocation, err := configuration.RecordInvocation(now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not record the use of the application: %s\n", err.Error())
+	}
+
+	if configuration.MaxSession == 0 || safeguardSkippedCommands[name] {
+		return
+	}
+
+	sessions, err := activityRepo.CurrentSessions()
+	if err != nil {
+		return
+	}
+
+	for i := range sessions {
+		checkForgottenSessionOf(&sessions[i], activityRepo, lastInvocation, now, configuration)
+	}
+}
+
+// checkForgottenSessionOf warns about one of the sessions being tracked if it has been running for too long
+func checkForgottenSessionOf(session *core.ActivityLog, activityRepo core.ActivityRepository, lastInvocation *time.Time, now time.Time, configuration config.Config) {
+	elapsed := session.Elapsed(now)
+	if elapsed <= configuration.MaxSession {
+		return
+	}
+
+	fmt.Fprintf(
+		os.Stderr,
+		"Warning: the session of '%s' has been running for %s, longer than the maximum of %v.\n",
+		session.Activity.Name,
+		utils.SecondsToHuman(int(elapsed.Seconds())),
+		configuration.MaxSession,
+	)
+
+	if !isInteractive() {
+		nameOrAlias := session.Activity.Name
+		if session.Activity.HasAlias() {
+			nameOrAlias = session.Activity.Alias
+		}
+		fmt.Fprintf(os.Stderr, "If you forgot to stop it, stop it when you actually did with: tt stop %q --at <TIME>\n", nameOrAlias)
+		return
+	}
+
+	stoppedAt, ok := askForgottenSessionStop(session, lastInvocation, configuration)
+	if !ok {
+		return
+	}
+
+	err := activityRepo.Stop(session.Activity, core.StopOptions{At: &stoppedAt})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not stop the session: %s\n", err.Error())
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Stopped '%s' at %s\n", session.Activity.Name, stoppedAt.Format("2006-01-02 15:04"))
+}
+
+// askForgottenSessionStop asks when the forgotten session should stop, and returns false if it should keep running
+func askForgottenSessionStop(session *core.ActivityLog, lastInvocation *time.Time, configuration config.Config) (time.Time, bool) {
+	useLastInvocation := lastInvocation != nil && lastInvocation.After(*session.StartedAt)
+	if useLastInvocation {
+		fmt.Fprintf(os.Stderr, "  1) stop it at the last time tt was used, %s\n", lastInvocation.In(configuration.Location).Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintln(os.Stderr, "  2) stop it at another time")
+	fmt.Fprintln(os.Stderr, "  3) leave it running")
+
+	input := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprint(os.Stderr, "Your choice: ")
+		choice, err := input.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(os.Stderr)
+			return time.Time{}, false
+		}
+
+		switch strings.TrimSpace(choice) {
+		case "1":
+			if useLastInvocation {
+				return *lastInvocation, true
+			}
+		case "2":
+			fmt.Fprint(os.Stderr, "Stop it at (a time like 17:45, \"10 hours ago\" or 2026-10-17 17:45): ")
+			value, err := input.ReadString('\n')
+			if err != nil {
+				fmt.Fprintln(os.Stderr)
+				return time.Time{}, false
+			}
+
+			at, err := parseAtFlag(strings.TrimSpace(value), configuration)
+			if err == nil && at != nil {
+				return *at, true
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		case "3":
+			return time.Time{}, false
+		}
+	}
+}
+
+// isInteractive returns true if the standard input is a terminal, where the user can answer questions
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	// The null device is a character device too, but nobody can answer from it
+	devNull, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, devNull)
+}
@@ -3,18 +3,40 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/luispcosta/go-tt/config"
 	"github.com/luispcosta/go-tt/core"
 	"github.com/spf13/cobra"
 )
 
+type startCommand struct {
+	note    string
+	at      string
+	until   string
+	forTime string
+	baseCmd *cobra.Command
+}
+
 // NewStartCommand starts tracking the time for an activity
-func NewStartCommand(activityRepo core.ActivityRepository) *cobra.Command {
-	deleteCmd := &cobra.Command{
+func NewStartCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	startCmd := &cobra.Command{
 		Use:   "start",
 		Short: "Starts an activity",
-		Long:  "Starts counting the time for an activity",
-		Args:  cobra.ExactArgs(1),
+		Long: `
+			Starts counting the time for an activity. You can describe what you are going to do with a note (-m <NOTE>).
+			The session can be tagged by passing tags prefixed with '+' after the activity, for example:
+			$ tt start coding +clientA +billable
+
+			If you forgot to start it on time, give when you actually started with --at, as in:
+			$ tt start coding --at "10 minutes ago"
+
+			To time-box the session, give how long it lasts with --for, or when it ends with --until:
+			$ tt start writing --for 45m
+			$ tt start writing --until 12:00
+			Once that instant has passed, the session is considered stopped at it.
+		`,
+		Args: cobra.MinimumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
 			activityNameOrAlias := args[0]
@@ -23,12 +45,38 @@ func NewStartCommand(activityRepo core.ActivityRepository) *cobra.Command {
 				fmt.Printf("Could not start activity. Error: %s\n", err.Error())
 				os.Exit(1)
 			}
-			errStart := activityRepo.Start(*activity)
+			tags, errTags := parseTagArguments(args[1:])
+			if errTags != nil {
+				fmt.Printf("Could not start activity. Error: %s\n", errTags.Error())
+				os.Exit(1)
+			}
+			at, errAt := parseAtFlag(cmd.Flag("at").Value.String(), configuration)
+			if errAt != nil {
+				fmt.Printf("Could not start activity. Error: %s\n", errAt.Error())
+				os.Exit(1)
+			}
+			startedAt := time.Now().In(configuration.Location)
+			if at != nil {
+				startedAt = *at
+			}
+			until, errUntil := parsePlannedEndFlags(cmd.Flag("for").Value.String(), cmd.Flag("until").Value.String(), startedAt)
+			if errUntil != nil {
+				fmt.Printf("Could not start activity. Error: %s\n", errUntil.Error())
+				os.Exit(1)
+			}
+			opts := core.StartOptions{Note: cmd.Flag("note").Value.String(), Tags: tags, At: at, Until: until}
+			errStart := activityRepo.Start(*activity, opts)
 			if errStart != nil {
 				fmt.Printf("Could not start activity with name and or alias %s - error: %s\n", activityNameOrAlias, errStart.Error())
 				os.Exit(1)
 			}
 		},
 	}
-	return deleteCmd
+	start := startCommand{}
+	startCmd.Flags().StringVarP(&start.note, "note", "m", "", "Note describing the session")
+	startCmd.Flags().StringVar(&start.at, "at", "", atFlagUsage)
+	startCmd.Flags().StringVar(&start.forTime, "for", "", "How long the session lasts, as in 45m or 1h30m, after which it is stopped")
+	startCmd.Flags().StringVar(&start.until, "until", "", "When the session ends, as a time of day (12:00) or a date and time (2026-10-17 12:00)")
+	start.baseCmd = startCmd
+	return startCmd
 }
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// statusProtocols are the status bars the status can be printed for
+var statusProtocols = []string{"waybar", "i3blocks", "polybar"}
+
+// Classes of the status, telling whether an activity is being tracked and whether it runs over its budget
+const (
+	statusIdle       = "idle"
+	statusRunning    = "running"
+	statusOverBudget = "over-budget"
+	statusError      = "error"
+)
+
+// statusColors are the colors of the classes, for the status bars that take a color instead of a class
+var statusColors = map[string]string{statusRunning: "#a3be8c", statusOverBudget: "#bf616a", statusError: "#bf616a"}
+
+type statusCommand struct {
+	protocol       string
+	format         string
+	idle           string
+	durationFormat string
+	budget         time.Duration
+	follow         bool
+	interval       time.Duration
+	baseCmd        *cobra.Command
+}
+
+// barStatus is what status bars show about the session being tracked
+type barStatus struct {
+	text    string
+	short   string
+	tooltip string
+	class   string
+}
+
+// NewStatusCommand prints the activity being tracked for status bars
+func NewStatusCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	status := statusCommand{}
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Prints the activity being tracked for status bars",
+		Long: fmt.Sprintf(`
+			Prints the activity being tracked in the format a status bar expects (--protocol), one of: %s.
+			The text is the activity started last with its elapsed time, and can be changed with a Go template (--format)
+			with the same fields as 'tt current --format'. The text given with --idle is shown when no activity is tracked.
+			Bars that support it get the time spent today on each activity as a tooltip, and a class telling whether
+			the activity is idle, running or over-budget. A session is over-budget when it runs for longer than the
+			"max_session" setting, or when the time spent on its activity today is over the --budget given.
+
+			With --follow, the status is printed again at every interval, for bars reading the output continuously, like
+			waybar without "interval", i3blocks with "interval=persist" or polybar with "tail = true":
+			$ tt status --protocol waybar --follow --interval 10s
+
+			Like every command, it first checks the schema version of the database, with a single query when the schema
+			is up to date, and migrates it if it is older.
+		`, strings.Join(statusProtocols, ", ")),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			if !isStatusProtocol(status.protocol) {
+				fmt.Printf("Unknown protocol '%s', expected one of: %s\n", status.protocol, strings.Join(statusProtocols, ", "))
+				os.Exit(1)
+			}
+
+			tmpl, err := template.New("status").Parse(status.format)
+			if err != nil {
+				fmt.Printf("Invalid format: %s\n", err.Error())
+				os.Exit(1)
+			}
+
+			if status.interval <= 0 {
+				fmt.Println("The interval must be positive")
+				os.Exit(1)
+			}
+
+			durationFormat := core.ParseDurationFormat(strings.ToLower(status.durationFormat))
+			for {
+				barStatus := status.read(activityRepo, tmpl, durationFormat, configuration)
+				printBarStatus(status.protocol, barStatus, status.follow)
+				if !status.follow {
+					return
+				}
+				time.Sleep(status.interval)
+			}
+		},
+	}
+	statusCmd.Flags().StringVar(&status.protocol, "protocol", "waybar", fmt.Sprintf("Status bar to print the status for: %s", strings.Join(statusProtocols, ", ")))
+	statusCmd.Flags().StringVar(&status.format, "format", "{{.Name}} {{.Elapsed}}", "Go template of the text, with the fields of 'tt current --format'")
+	statusCmd.Flags().StringVar(&status.idle, "idle", "", "Text shown when no activity is being tracked")
+	statusCmd.Flags().StringVarP(&status.durationFormat, "durationFormat", "d", core.Clock, "Duration format of the text and the tooltip")
+	statusCmd.Flags().DurationVar(&status.budget, "budget", 0, "Time that can be spent on an activity in a day before it is over-budget, as in 4h")
+	statusCmd.Flags().BoolVar(&status.follow, "follow", false, "Print the status again at every interval")
+	statusCmd.Flags().DurationVar(&status.interval, "interval", 5*time.Second, "How often the status is printed with --follow")
+	status.baseCmd = statusCmd
+	return statusCmd
+}
+
+// read returns the status of the session started last, with the time spent today on each activity as its tooltip.
+// Only read-only queries are made, so that refreshing the status does not hold up the commands writing to the database.
+func (status statusCommand) read(activityRepo core.ActivityRepository, tmpl *template.Template, durationFormat core.DurationFormat, configuration config.Config) barStatus {
+	now := time.Now().In(configuration.Location)
+	today, err := parsePeriodArguments(nil, configuration)
+	if err != nil {
+		return barStatus{text: err.Error(), class: statusError}
+	}
+
+	logs, err := activityRepo.CurrentActivityLogs(today.Interval().Start)
+	if err != nil {
+		return barStatus{text: err.Error(), class: statusError}
+	}
+
+	var running []core.ActivityLog
+	for _, log := range core.SettleOverdueLogs(logs, now) {
+		if log.IsRunning() {
+			running = append(running, log)
+		}
+	}
+
+	totals, err := todayTotals(activityRepo, running, now, configuration)
+	if err != nil {
+		return barStatus{text: err.Error(), class: statusError}
+	}
+
+	tooltip := []string{"Today"}
+	total := 0
+	for _, activityTotal := range totals {
+		tooltip = append(tooltip, fmt.Sprintf("%s %s", activityTotal.name, strings.TrimSpace(durationFormat.Format(activityTotal.seconds))))
+		total += activityTotal.seconds
+	}
+	tooltip = append(tooltip, fmt.Sprintf("Total %s", strings.TrimSpace(durationFormat.Format(total))))
+
+	session := core.NewSessionStatus(logs, today, now)
+	if !session.Tracking {
+		return barStatus{text: status.idle, short: status.idle, tooltip: strings.Join(tooltip, "\n"), class: statusIdle}
+	}
+
+	for _, activityTotal := range totals {
+		if activityTotal.name == session.Activity.Name {
+			session.Today = time.Duration(activityTotal.seconds) * time.Second
+		}
+	}
+
+	var text strings.Builder
+	err = tmpl.Execute(&text, newCurrentStatus(session, durationFormat, configuration))
+	if err != nil {
+		return barStatus{text: fmt.Sprintf("Invalid format: %s", err.Error()), class: statusError}
+	}
+
+	class := statusRunning
+	overSession := configuration.MaxSession > 0 && session.Elapsed > configuration.MaxSession
+	overBudget := status.budget > 0 && session.Today > status.budget
+	if overSession || overBudget {
+		class = statusOverBudget
+	}
+
+	return barStatus{text: text.String(), short: session.Activity.Name, tooltip: strings.Join(tooltip, "\n"), class: class}
+}
+
+// isStatusProtocol returns true if the status can be printed for the status bar
+func isStatusProtocol(protocol string) bool {
+	for _, known := range statusProtocols {
+		if known == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// printBarStatus prints the status in the format the status bar expects. Bars following the output get a single
+// line each time.
+func printBarStatus(protocol string, status barStatus, follow bool) {
+	switch protocol {
+	case "waybar":
+		output, _ := json.Marshal(struct {
+			Text    string `json:"text"`
+			Alt     string `json:"alt"`
+			Tooltip string `json:"tooltip"`
+			Class   string `json:"class"`
+		}{status.text, status.class, status.tooltip, status.class})
+		fmt.Println(string(output))
+	case "i3blocks":
+		if follow {
+			fmt.Println(status.text)
+			return
+		}
+		fmt.Printf("%s\n%s\n%s\n", status.text, status.short, statusColors[status.class])
+	case "polybar":
+		// Polybar reads %{...} as formatting tags, so the percent signs of the text are escaped
+		text := strings.ReplaceAll(status.text, "%", "%%")
+		if color, ok := statusColors[status.class]; ok && status.class != statusRunning {
+			fmt.Printf("%%{F%s}%s%%{F-}\n", color, text)
+			return
+		}
+		fmt.Println(text)
+	}
+}
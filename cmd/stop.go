@@ -3,32 +3,80 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/luispcosta/go-tt/config"
 	"github.com/luispcosta/go-tt/core"
 	"github.com/spf13/cobra"
 )
 
+type stopCommand struct {
+	note    string
+	at      string
+	baseCmd *cobra.Command
+}
+
 // NewStopCommand stops tracking an activity
-func NewStopCommand(activityRepo core.ActivityRepository) *cobra.Command {
+func NewStopCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
 	stopCmd := &cobra.Command{
 		Use:   "stop",
 		Short: "Stop an activity",
-		Long:  "Stops counting the time for an activity",
-		Args:  cobra.ExactArgs(1),
+		Long: `
+			Stops counting the time for the activity being tracked. The activity can be given, to make sure it is the one being stopped,
+			and must be given when several activities are being tracked at once.
+			A note (-m <NOTE>) is added to the note the session may already have.
+
+			If you forgot to stop it on time, give when you actually stopped with --at, as in:
+			$ tt stop --at 17:45
+		`,
+		Args: cobra.MaximumNArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
-			activityName := args[0]
-			activity, err := activityRepo.Find(activityName)
-			if err != nil {
-				fmt.Printf("Could not find activity with name or alias %s\n", activityName)
+			var activity *core.Activity
+			var err error
+			if len(args) == 0 {
+				sessions, errSessions := activityRepo.CurrentSessions()
+				if errSessions != nil {
+					fmt.Println(errSessions)
+					os.Exit(1)
+				}
+				if len(sessions) == 0 {
+					fmt.Println("Not currently tracking any activity")
+					os.Exit(1)
+				}
+				if len(sessions) > 1 {
+					var names []string
+					for _, session := range sessions {
+						names = append(names, session.Activity.Name)
+					}
+					fmt.Printf("You are tracking several activities (%s), please give the one to stop\n", strings.Join(names, ", "))
+					os.Exit(1)
+				}
+				activity = &sessions[0].Activity
+			} else {
+				activity, err = activityRepo.Find(args[0])
+				if err != nil {
+					fmt.Printf("Could not find activity with name or alias %s\n", args[0])
+					os.Exit(1)
+				}
+			}
+			activityName := activity.Name
+			at, errAt := parseAtFlag(cmd.Flag("at").Value.String(), configuration)
+			if errAt != nil {
+				fmt.Printf("Could not stop activity. Error: %s\n", errAt.Error())
 				os.Exit(1)
 			}
-			errStop := activityRepo.Stop(*activity)
+			opts := core.StopOptions{Note: cmd.Flag("note").Value.String(), At: at}
+			errStop := activityRepo.Stop(*activity, opts)
 			if errStop != nil {
 				fmt.Printf("Could not stop activity with name or alias %s - error: %s\n", activityName, errStop.Error())
 				os.Exit(1)
 			}
 		},
 	}
+	stop := stopCommand{}
+	stopCmd.Flags().StringVarP(&stop.note, "note", "m", "", "Note describing the session")
+	stopCmd.Flags().StringVar(&stop.at, "at", "", atFlagUsage)
+	stop.baseCmd = stopCmd
 	return stopCmd
 }
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+type switchCommand struct {
+	note    string
+	at      string
+	baseCmd *cobra.Command
+}
+
+// NewSwitchCommand stops tracking the current activity and starts tracking another one
+func NewSwitchCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	switchCmd := &cobra.Command{
+		Use:   "switch",
+		Short: "Stops the current activity and starts another one",
+		Long: `
+			Stops counting the time for the activity being tracked, if any, and starts counting it for another activity at the same instant.
+			Like with the 'start' command, the new session can have a note (-m <NOTE>) and tags, for example:
+			$ tt switch reading +books -m "chapter 2"
+
+			If you forgot to switch on time, give when you actually switched with --at, as in:
+			$ tt switch reading --at 14:30
+		`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			activityNameOrAlias := args[0]
+			activity, err := activityRepo.Find(activityNameOrAlias)
+			if err != nil {
+				fmt.Printf("Could not switch activity. Error: %s\n", err.Error())
+				os.Exit(1)
+			}
+			tags, errTags := parseTagArguments(args[1:])
+			if errTags != nil {
+				fmt.Printf("Could not switch activity. Error: %s\n", errTags.Error())
+				os.Exit(1)
+			}
+			at, errAt := parseAtFlag(cmd.Flag("at").Value.String(), configuration)
+			if errAt != nil {
+				fmt.Printf("Could not switch activity. Error: %s\n", errAt.Error())
+				os.Exit(1)
+			}
+			opts := core.StartOptions{Note: cmd.Flag("note").Value.String(), Tags: tags, At: at}
+			errSwitch := activityRepo.Switch(*activity, opts)
+			if errSwitch != nil {
+				fmt.Printf("Could not switch to activity with name or alias %s - error: %s\n", activityNameOrAlias, errSwitch.Error())
+				os.Exit(1)
+			}
+		},
+	}
+	switchOpts := switchCommand{}
+	switchCmd.Flags().StringVarP(&switchOpts.note, "note", "m", "", "Note describing the session")
+	switchCmd.Flags().StringVar(&switchOpts.at, "at", "", atFlagUsage)
+	switchOpts.baseCmd = switchCmd
+	return switchCmd
+}
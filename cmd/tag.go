@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// NewTagCommand manages the tags of activities
+func NewTagCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	tagCmd := &cobra.Command{
+		Use:   "tag",
+		Short: "Manages tags",
+		Long: `
+			Tags group activities and sessions across activities, so that reports can be filtered by them.
+			Tags are given prefixed with '+', and may only contain alpha numeric characters, '-' or '_'.
+			Sessions are tagged when they start, for example: $ tt start coding +clientA
+		`,
+	}
+
+	tagCmd.AddCommand(&cobra.Command{
+		Use:   "add",
+		Short: "Adds tags to an activity",
+		Long:  "Adds tags to an activity, for example: $ tt tag add coding +work +billable",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			activity, tags := findActivityAndTags(activityRepo, args)
+			err := activityRepo.TagActivity(*activity, tags)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	})
+
+	tagCmd.AddCommand(&cobra.Command{
+		Use:   "rm",
+		Short: "Removes tags from an activity",
+		Long:  "Removes tags from an activity, for example: $ tt tag rm coding +billable. The sessions keep their own tags.",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			activity, tags := findActivityAndTags(activityRepo, args)
+			err := activityRepo.UntagActivity(*activity, tags)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	})
+
+	tagCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "Lists all tags",
+		Long:  "Lists all the tags in use by activities or sessions",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			tags, err := activityRepo.Tags()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, tag := range tags {
+				fmt.Println(core.TagPrefix + tag)
+			}
+		},
+	})
+
+	tagCmd.AddCommand(&cobra.Command{
+		Use:   "rename",
+		Short: "Renames a tag",
+		Long:  "Renames a tag in all activities and sessions. Renaming a tag to an existing one merges both. Example: $ tt tag rename +clienta +acme",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			tags := make([]string, len(args))
+			for i, arg := range args {
+				tag, err := core.ParseTag(arg)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				tags[i] = tag
+			}
+
+			err := activityRepo.RenameTag(tags[0], tags[1])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	})
+
+	return tagCmd
+}
+
+// findActivityAndTags finds the activity named by the first argument, and parses the remaining ones as tags
+func findActivityAndTags(activityRepo core.ActivityRepository, args []string) (*core.Activity, []string) {
+	activity, err := activityRepo.Find(args[0])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	tags, err := parseTagArguments(args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return activity, tags
+}
+
+// parseTagArguments parses command line arguments that must all be tags prefixed with '+'
+func parseTagArguments(args []string) ([]string, error) {
+	for _, arg := range args {
+		if !core.IsTagArgument(arg) {
+			return nil, fmt.Errorf("unexpected argument '%s', tags must be prefixed with '%s'", arg, core.TagPrefix)
+		}
+	}
+	return core.ParseTags(args)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Escape sequences used to draw full-screen views in the terminal
+const (
+	enterAlternateScreen = "\033[?1049h\033[?25l"
+	leaveAlternateScreen = "\033[?25h\033[?1049l"
+	clearScreen          = "\033[H\033[2J"
+)
+
+// enterKeyMode makes the terminal send each key as soon as it is pressed, without echoing it, and returns
+// a function restoring the previous mode. Signals such as Ctrl-C are still delivered.
+func enterKeyMode() (func(), error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = stty("-icanon", "-echo", "min", "1", "time", "0")
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		stty(strings.TrimSpace(state))
+	}, nil
+}
+
+// stty runs stty on the terminal of the standard input, and returns its output
+func stty(args ...string) (string, error) {
+	command := exec.Command("stty", args...)
+	command.Stdin = os.Stdin
+	output, err := command.Output()
+	return string(output), err
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"sort"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+)
+
+// activityTotal is the time spent on an activity, in seconds
+type activityTotal struct {
+	name    string
+	seconds int
+}
+
+// todayTotals returns the time spent today on each activity, the given running sessions included, longest first
+func todayTotals(activityRepo core.ActivityRepository, running []core.ActivityLog, now time.Time, configuration config.Config) ([]activityTotal, error) {
+	today, err := parsePeriodArguments(nil, configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	overlap, err := core.ParseOverlapMode(configuration.Overlap)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregations, err := activityRepo.LogsForPeriod(today, core.LogFilter{Overlap: overlap})
+	if err != nil {
+		return nil, err
+	}
+
+	seconds := make(map[string]int)
+	for _, dayAggregations := range aggregations {
+		for _, aggregation := range dayAggregations {
+			seconds[aggregation.Activity.Name] += aggregation.Duration
+		}
+	}
+	for _, session := range running {
+		seconds[session.Activity.Name] += int(session.ElapsedIn(today, now).Seconds())
+	}
+
+	var totals []activityTotal
+	for name, activitySeconds := range seconds {
+		totals = append(totals, activityTotal{name: name, seconds: activitySeconds})
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].seconds != totals[j].seconds {
+			return totals[i].seconds > totals[j].seconds
+		}
+		return totals[i].name < totals[j].name
+	})
+	return totals, nil
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/spf13/cobra"
+)
+
+// NewUnarchiveCommand restores an archived activity
+func NewUnarchiveCommand(activityRepo core.ActivityRepository) *cobra.Command {
+	unarchiveCmd := &cobra.Command{
+		Use:   "unarchive",
+		Short: "Restores an archived activity",
+		Long:  "Restores an archived activity, along with its sub-activities, so that it is listed and can be started again",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			err := activityRepo.Unarchive(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Activity %s restored\n", args[0])
+		},
+	}
+	return unarchiveCmd
+}
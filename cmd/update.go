@@ -12,6 +12,7 @@ import (
 type updateCommand struct {
 	name        string
 	description string
+	concurrent  bool
 	baseCmd     *cobra.Command
 }
 
@@ -19,12 +20,27 @@ type updateCommand struct {
 func NewUpdateCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	updateCmd := &cobra.Command{
 		Use:   "update",
-		Short: "Updates the metadata of an activity. Only the name and description can be updated.",
-		Args:  cobra.ExactArgs(1),
+		Short: "Updates the metadata of an activity. Only the name, the description and whether it is concurrent can be updated.",
+		Long: `
+			Updates the metadata of an activity. Only the name, the description and whether it is concurrent can be updated.
+			Renaming an activity also renames its sub-activities, and can move it under another activity,
+			for example: $ tt update work/backend --name job/backend
+			A concurrent activity can be tracked at the same time as other activities: $ tt update on-call --concurrent=true
+		`,
+		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
 			name := cmd.Flag("name")
 			description := cmd.Flag("desc")
+			if name.Changed {
+				renamed := core.Activity{Name: name.Value.String()}
+				errName := renamed.ValidateName()
+				if errName != nil {
+					fmt.Println(errName)
+					os.Exit(1)
+				}
+				name.Value.Set(renamed.Name)
+			}
 			var updateOp core.UpdateActivity
 			if name.Changed && description.Changed {
 				updateOp = core.UpdateActivityNameAndDescription{Name: name.Value.String(), Desc: description.Value.String()}
@@ -35,6 +51,10 @@ func NewUpdateCommand(activityRepo core.ActivityRepository) *cobra.Command {
 			} else {
 				updateOp = core.NoActivityUpdate{}
 			}
+			if concurrent := cmd.Flag("concurrent"); concurrent.Changed {
+				isConcurrent, _ := cmd.Flags().GetBool("concurrent")
+				updateOp = core.UpdateActivityFields{updateOp, core.UpdateActivityConcurrent{Concurrent: isConcurrent}}
+			}
 
 			errUpdate := activityRepo.Update(args[0], updateOp)
 			if errUpdate != nil {
@@ -47,6 +67,7 @@ func NewUpdateCommand(activityRepo core.ActivityRepository) *cobra.Command {
 	upd := updateCommand{}
 	updateCmd.Flags().StringVarP(&upd.name, "name", "n", "", "Activity name")
 	updateCmd.Flags().StringVarP(&upd.description, "desc", "d", "xx", "Activity description")
+	updateCmd.Flags().BoolVar(&upd.concurrent, "concurrent", false, "Whether the activity can be tracked at the same time as other activities")
 	upd.baseCmd = updateCmd
 	return updateCmd
 }
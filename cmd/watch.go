@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
+	"github.com/spf13/cobra"
+)
+
+// watchView is the state of the full-screen view of the watch command
+type watchView struct {
+	activityRepo  core.ActivityRepository
+	configuration config.Config
+	// message is the outcome of the last action, shown until the next one
+	message string
+	// input is the name of the activity being typed to switch to, nil when not switching
+	input *string
+}
+
+// NewWatchCommand shows a live view of the activities being tracked
+func NewWatchCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
+	watchCmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Shows a live view of the activities being tracked",
+		Long: `
+			Keeps a full-screen view open in the terminal, refreshed every second, with the activities being tracked,
+			their elapsed time, and the time spent today on each activity.
+			Without leaving the view, press 's' to stop the activity started last, 'p' to pause or resume,
+			'w' to switch to another activity, and 'q' to quit.
+		`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ExitIfAppNotConfigured()
+			if !isInteractive() {
+				fmt.Println("The watch command needs to run in a terminal")
+				os.Exit(1)
+			}
+
+			restore, err := enterKeyMode()
+			if err != nil {
+				fmt.Printf("Could not set up the terminal - error: %s\n", err.Error())
+				os.Exit(1)
+			}
+			fmt.Print(enterAlternateScreen)
+			defer func() {
+				fmt.Print(leaveAlternateScreen)
+				restore()
+			}()
+
+			view := watchView{activityRepo: activityRepo, configuration: configuration}
+			view.run()
+		},
+	}
+	return watchCmd
+}
+
+// run refreshes the view every second and handles the keys pressed, until the user quits
+func (view *watchView) run() {
+	keys := make(chan byte)
+	go func() {
+		buffer := make([]byte, 1)
+		for {
+			n, err := os.Stdin.Read(buffer)
+			if err != nil {
+				close(keys)
+				return
+			}
+			if n == 1 {
+				keys <- buffer[0]
+			}
+		}
+	}()
+
+	interrupted := make(chan os.Signal, 1)
+	signal.Notify(interrupted, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupted)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		view.render()
+		select {
+		case <-ticker.C:
+		case <-interrupted:
+			return
+		case key, ok := <-keys:
+			if !ok || !view.handleKey(key) {
+				return
+			}
+		}
+	}
+}
+
+// handleKey applies the key pressed, and returns false if the user quits
+func (view *watchView) handleKey(key byte) bool {
+	if view.input != nil {
+		switch key {
+		case '\n', '\r':
+			name := strings.TrimSpace(*view.input)
+			view.input = nil
+			if name != "" {
+				view.switchTo(name)
+			}
+		case 27:
+			view.input = nil
+			view.message = ""
+		case 127, '\b':
+			if len(*view.input) > 0 {
+				*view.input = (*view.input)[:len(*view.input)-1]
+			}
+		default:
+			if key >= ' ' {
+				*view.input += string(key)
+			}
+		}
+		return true
+	}
+
+	switch key {
+	case 'q':
+		return false
+	case 's':
+		view.stop()
+	case 'p':
+		view.togglePause()
+	case 'w':
+		input := ""
+		view.input = &input
+	}
+	return true
+}
+
+// stop stops the session started last
+func (view *watchView) stop() {
+	session, err := view.activityRepo.CurrentSession()
+	if err != nil {
+		view.message = err.Error()
+		return
+	}
+	if session == nil {
+		view.message = "Not currently tracking any activity"
+		return
+	}
+
+	err = view.activityRepo.Stop(session.Activity, core.StopOptions{})
+	if err != nil {
+		view.message = fmt.Sprintf("Could not stop activity %s - error: %s", session.Activity.Name, err.Error())
+		return
+	}
+	view.message = fmt.Sprintf("Stopped '%s'", session.Activity.Name)
+}
+
+// togglePause pauses the sessions being tracked, or resumes them if they are all paused
+func (view *watchView) togglePause() {
+	sessions, err := view.activityRepo.CurrentSessions()
+	if err != nil {
+		view.message = err.Error()
+		return
+	}
+
+	if core.CheckPause(sessions) == nil {
+		err = view.activityRepo.Pause()
+		view.message = "Paused"
+	} else {
+		err = view.activityRepo.Resume()
+		view.message = "Resumed"
+	}
+	if err != nil {
+		view.message = err.Error()
+	}
+}
+
+// switchTo stops the activities being tracked, unless concurrent, and starts the one with the given name or alias
+func (view *watchView) switchTo(nameOrAlias string) {
+	activity, err := view.activityRepo.Find(nameOrAlias)
+	if err != nil {
+		view.message = fmt.Sprintf("Could not switch activity. Error: %s", err.Error())
+		return
+	}
+
+	err = view.activityRepo.Switch(*activity, core.StartOptions{})
+	if err != nil {
+		view.message = fmt.Sprintf("Could not switch to activity %s - error: %s", activity.Name, err.Error())
+		return
+	}
+	view.message = fmt.Sprintf("Switched to '%s'", activity.Name)
+}
+
+// render draws the whole view
+func (view *watchView) render() {
+	now := time.Now().In(view.configuration.Location)
+	var screen strings.Builder
+	screen.WriteString(clearScreen)
+	fmt.Fprintf(&screen, "tt watch - %s\n\n", now.Format("Monday 2006-01-02 15:04:05"))
+
+	sessions, errSessions := view.activityRepo.CurrentSessions()
+	totals, errTotals := todayTotals(view.activityRepo, sessions, now, view.configuration)
+
+	screen.WriteString("Tracking\n")
+	if len(sessions) == 0 {
+		screen.WriteString("  Not currently tracking any activity\n")
+	}
+	for _, session := range sessions {
+		status := fmt.Sprintf("%selapsed", utils.SecondsToHuman(int(session.Elapsed(now).Seconds())))
+		if session.IsPaused() {
+			status = "paused, " + status
+		}
+		if left, ok := session.Remaining(now); ok {
+			status += fmt.Sprintf(", %sleft", utils.SecondsToHuman(int(left.Seconds())))
+		}
+		fmt.Fprintf(&screen, "  %-24s %s\n", session.Activity.Name, status)
+	}
+
+	screen.WriteString("\nToday\n")
+	if len(totals) == 0 {
+		screen.WriteString("  Nothing tracked yet\n")
+	}
+	total := 0
+	for _, activityTotal := range totals {
+		fmt.Fprintf(&screen, "  %-24s %s\n", activityTotal.name, utils.SecondsToHuman(activityTotal.seconds))
+		total += activityTotal.seconds
+	}
+	if len(totals) > 1 {
+		fmt.Fprintf(&screen, "  %-24s %s\n", "Total", utils.SecondsToHuman(total))
+	}
+
+	screen.WriteString("\n")
+	for _, err := range []error{errSessions, errTotals} {
+		if err != nil {
+			fmt.Fprintf(&screen, "%s\n", err.Error())
+		}
+	}
+	if view.message != "" {
+		fmt.Fprintf(&screen, "%s\n", view.message)
+	}
+
+	if view.input != nil {
+		fmt.Fprintf(&screen, "Switch to (Enter to confirm, Esc to cancel): %s", *view.input)
+	} else {
+		screen.WriteString("[s] stop  [p] pause/resume  [w] switch  [q] quit")
+	}
+	fmt.Print(screen.String())
+}
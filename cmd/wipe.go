@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/luispcosta/go-tt/config"
 	"github.com/luispcosta/go-tt/core"
 	"github.com/spf13/cobra"
 )
@@ -14,15 +15,19 @@ type wipeCommand struct {
 }
 
 // NewWipeCommand deletes log data for a given period
-func NewWipeCommand(activityRepo core.ActivityRepository) *cobra.Command {
+func NewWipeCommand(activityRepo core.ActivityRepository, configuration config.Config) *cobra.Command {
 	wipeCmd := &cobra.Command{
 		Use:   "wipe",
 		Short: "Deletes logs for a given period, and for a sepcific activity (optional)",
-		Args:  cobra.ExactArgs(2),
+		Long: fmt.Sprintf(`
+			Deletes the logs of a period, of all the activities or only of the given one (-a <ACTIVITY>).
+			%s
+		`, periodArgumentsHelp),
+		Args: cobra.RangeArgs(1, 2),
 		Run: func(cmd *cobra.Command, args []string) {
 			ExitIfAppNotConfigured()
 			alias := cmd.Flag("activity").Value.String()
-			period, errPeriod := core.PeriodFromDateStrings(args[0], args[1])
+			period, errPeriod := parsePeriodArguments(args, configuration)
 			if errPeriod != nil {
 				fmt.Println(errPeriod)
 				os.Exit(1)
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/luispcosta/go-tt/utils"
 )
@@ -10,10 +14,46 @@ import (
 // Config is a base struct with configuration options for the application.
 type Config struct {
 	UserDataLocation string
+	// Location is the time zone used to decide which day a session belongs to.
+	Location *time.Location
+	// DateFormats are the layouts dates can be given in, on top of 2006-01-02.
+	DateFormats []string
+	// WeekStart is the first day of the week of calendar periods, as in this-week.
+	WeekStart time.Weekday
+	// MaxSession is the duration above which a session is considered forgotten, zero for no limit.
+	MaxSession time.Duration
+	// ConcurrentTimers lets sessions of any activities run at the same time, not only of concurrent activities.
+	ConcurrentTimers bool
+	// Overlap is how reports count the time during which several sessions were running: "full" or "split".
+	Overlap string
+	// SkipMigrations opens the database without migrating its schema, for the commands managing the schema themselves.
+	SkipMigrations bool
+}
+
+// settings represents the user editable settings file.
+type settings struct {
+	TimeZone    string   `json:"timezone"`
+	DateFormats []string `json:"date_formats"`
+	WeekStart   string   `json:"week_start"`
+	MaxSession  string   `json:"max_session"`
+	Concurrent  bool     `json:"concurrent_timers"`
+	Overlap     string   `json:"overlap"`
 }
 
 const ConfigFolder = ".gott"
 
+// SettingsFileName is the name of the optional settings file, inside the user data location.
+const SettingsFileName = "config.json"
+
+// Ways reports count the time during which several sessions were running, either fully in each of them or split between them
+const (
+	OverlapFull  = "full"
+	OverlapSplit = "split"
+)
+
+// InvocationFileName is the name of the file keeping the last time the application was used, inside the user data location.
+const InvocationFileName = "last_invocation"
+
 // NewConfig returns a new app configuration.
 func NewConfig() Config {
 	config := Config{}
@@ -50,7 +90,109 @@ func (config *Config) Setup() error {
 	return utils.CreateDir(config.UserDataLocation)
 }
 
+// SettingsFile returns the path of the settings file
+func (config *Config) SettingsFile() string {
+	return fmt.Sprintf("%s%s", config.UserDataLocation, SettingsFileName)
+}
+
+// LoadSettings overrides the default configuration values with the ones in the settings file, if it exists.
+func (config *Config) LoadSettings() error {
+	exists, _ := utils.PathExists(config.SettingsFile())
+	if !exists {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(config.SettingsFile())
+	if err != nil {
+		return err
+	}
+
+	var s settings
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return fmt.Errorf("invalid settings file %s: %w", config.SettingsFile(), err)
+	}
+
+	if s.TimeZone != "" {
+		location, err := time.LoadLocation(s.TimeZone)
+		if err != nil {
+			return fmt.Errorf("invalid time zone '%s' in settings file: %w", s.TimeZone, err)
+		}
+		config.Location = location
+	}
+
+	for _, format := range s.DateFormats {
+		layout, err := dateFormatLayout(format)
+		if err != nil {
+			return fmt.Errorf("invalid date format '%s' in settings file: %w", format, err)
+		}
+		config.DateFormats = append(config.DateFormats, layout)
+	}
+
+	if s.WeekStart != "" {
+		weekStart, ok := utils.ParseWeekday(s.WeekStart)
+		if !ok {
+			return fmt.Errorf("invalid week start '%s' in settings file: expected the name of a day of the week, like monday", s.WeekStart)
+		}
+		config.WeekStart = weekStart
+	}
+
+	if s.MaxSession != "" {
+		maxSession, err := time.ParseDuration(s.MaxSession)
+		if err != nil || maxSession < 0 {
+			return fmt.Errorf("invalid max session '%s' in settings file, expected a duration like 10h", s.MaxSession)
+		}
+		config.MaxSession = maxSession
+	}
+
+	config.ConcurrentTimers = s.Concurrent
+	if s.Overlap != "" {
+		if s.Overlap != OverlapFull && s.Overlap != OverlapSplit {
+			return fmt.Errorf("invalid overlap '%s' in settings file, expected %s or %s", s.Overlap, OverlapFull, OverlapSplit)
+		}
+		config.Overlap = s.Overlap
+	}
+
+	return nil
+}
+
+// RecordInvocation saves the given instant as the last time the application was used, and returns the previous one,
+// or nil if it was never recorded.
+func (config *Config) RecordInvocation(now time.Time) (*time.Time, error) {
+	file := fmt.Sprintf("%s%s", config.UserDataLocation, InvocationFileName)
+	var previous *time.Time
+	data, err := ioutil.ReadFile(file)
+	if err == nil {
+		instant, errParse := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+		if errParse == nil {
+			previous = &instant
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return previous, ioutil.WriteFile(file, []byte(now.Format(time.RFC3339)), 0644)
+}
+
+// dateFormatTokens are the parts of a date format, as in DD/MM/YYYY, with their Go layout
+var dateFormatTokens = [][2]string{{"YYYY", "2006"}, {"MM", "01"}, {"DD", "02"}}
+
+// dateFormatLayout returns the Go layout of a date format written with YYYY, MM and DD, as in DD/MM/YYYY
+func dateFormatLayout(format string) (string, error) {
+	layout := format
+	for _, token := range dateFormatTokens {
+		if strings.Count(layout, token[0]) != 1 {
+			return "", fmt.Errorf("it must have %s exactly once", token[0])
+		}
+		layout = strings.Replace(layout, token[0], token[1], 1)
+	}
+	return layout, nil
+}
+
 func initConfigWithDefaultValues(config *Config) {
 	homeDir := utils.HomeDir()
 	config.UserDataLocation = fmt.Sprintf("%s%s%s%s", homeDir, string(os.PathSeparator), ConfigFolder, string(os.PathSeparator))
+	config.Location = time.Local
+	config.WeekStart = time.Monday
+	config.Overlap = OverlapFull
 }
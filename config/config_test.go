@@ -0,0 +1,149 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestLoadSettingsWithoutSettingsFile(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+
+	err := config.LoadSettings()
+
+	if err != nil {
+		t.Errorf("Should not fail when there is no settings file: %v", err)
+	}
+
+	if config.Location != time.Local {
+		t.Error("Should default to the local time zone")
+	}
+}
+
+func TestLoadSettingsTimeZone(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"timezone": "UTC"}`)
+
+	err := config.LoadSettings()
+
+	if err != nil {
+		t.Fatalf("Should have loaded the settings: %v", err)
+	}
+
+	if config.Location != time.UTC {
+		t.Errorf("Should have loaded the UTC time zone, got %v", config.Location)
+	}
+}
+
+func TestLoadSettingsInvalidTimeZone(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"timezone": "Nowhere/Somewhere"}`)
+
+	err := config.LoadSettings()
+
+	if err == nil {
+		t.Error("Should have failed to load an unknown time zone")
+	}
+}
+
+func TestLoadSettingsDateFormats(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"date_formats": ["DD/MM/YYYY", "YYYY.MM.DD"]}`)
+
+	err := config.LoadSettings()
+
+	if err != nil {
+		t.Fatalf("Should have loaded the settings: %v", err)
+	}
+
+	if len(config.DateFormats) != 2 || config.DateFormats[0] != "02/01/2006" || config.DateFormats[1] != "2006.01.02" {
+		t.Errorf("Should have loaded the date formats as layouts, got %v", config.DateFormats)
+	}
+}
+
+func TestLoadSettingsInvalidDateFormat(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"date_formats": ["DD/MM"]}`)
+
+	err := config.LoadSettings()
+
+	if err == nil {
+		t.Error("Should have failed to load a date format without a year")
+	}
+}
+
+func TestLoadSettingsWeekStart(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local, WeekStart: time.Monday}
+	writeSettings(t, config, `{"week_start": "Sunday"}`)
+
+	err := config.LoadSettings()
+
+	if err != nil {
+		t.Fatalf("Should have loaded the settings: %v", err)
+	}
+
+	if config.WeekStart != time.Sunday {
+		t.Errorf("Should have loaded Sunday as the first day of the week, got %v", config.WeekStart)
+	}
+
+	writeSettings(t, config, `{"week_start": "someday"}`)
+	if config.LoadSettings() == nil {
+		t.Error("Should have failed to load an unknown day of the week")
+	}
+}
+
+func TestLoadSettingsMaxSession(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"max_session": "10h30m"}`)
+
+	err := config.LoadSettings()
+
+	if err != nil || config.MaxSession != 10*time.Hour+30*time.Minute {
+		t.Errorf("Should have loaded the max session, got %v (%v)", config.MaxSession, err)
+	}
+
+	writeSettings(t, config, `{"max_session": "long"}`)
+	if config.LoadSettings() == nil {
+		t.Error("Should have failed to load an invalid max session")
+	}
+}
+
+func TestLoadSettingsConcurrentTimersAndOverlap(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	writeSettings(t, config, `{"concurrent_timers": true, "overlap": "split"}`)
+
+	err := config.LoadSettings()
+
+	if err != nil || !config.ConcurrentTimers || config.Overlap != OverlapSplit {
+		t.Errorf("Should have loaded concurrent timers and the overlap, got %v %s (%v)", config.ConcurrentTimers, config.Overlap, err)
+	}
+
+	writeSettings(t, config, `{"overlap": "half"}`)
+	if config.LoadSettings() == nil {
+		t.Error("Should have failed to load an invalid overlap")
+	}
+}
+
+func TestRecordInvocation(t *testing.T) {
+	config := Config{UserDataLocation: fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator)), Location: time.Local}
+	first := time.Date(2026, 10, 17, 18, 5, 0, 0, time.UTC)
+
+	previous, err := config.RecordInvocation(first)
+	if err != nil || previous != nil {
+		t.Fatalf("There should be no previous invocation, got %v (%v)", previous, err)
+	}
+
+	previous, err = config.RecordInvocation(first.Add(14 * time.Hour))
+	if err != nil || previous == nil || !previous.Equal(first) {
+		t.Errorf("Should have returned the previous invocation, got %v (%v)", previous, err)
+	}
+}
+
+func writeSettings(t *testing.T, config Config, content string) {
+	err := ioutil.WriteFile(config.SettingsFile(), []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
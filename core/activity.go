@@ -7,12 +7,47 @@ import (
 	"strings"
 )
 
-// Activity represents an activity done by the user is some point in time
+// ActivitySeparator separates the names of an activity and of its parents in its full name, as in "work/backend/reviews"
+const ActivitySeparator = "/"
+
+// Activity represents an activity done by the user is some point in time.
+// Sub-activities are named after their parent, for example "work/backend" is a sub-activity of "work".
 type Activity struct {
-	Name        string `json:"name""`
-	Alias       string `json:"alias""`
-	Description string `json:"description"`
-	Id          int    `json:"id"`
+	Name        string   `json:"name"`
+	Alias       string   `json:"alias"`
+	Description string   `json:"description"`
+	Id          int      `json:"id"`
+	Tags        []string `json:"tags"`
+	// Archived activities are hidden and cannot be tracked, but their history is kept
+	Archived bool `json:"archived"`
+	// Concurrent activities can be tracked at the same time as other activities
+	Concurrent bool `json:"concurrent"`
+}
+
+// DeleteOptions chooses what happens to the logs of an activity when it is deleted.
+// An activity with logs can only be deleted if one of the options is set.
+type DeleteOptions struct {
+	// DeleteLogs deletes the logs along with the activity
+	DeleteLogs bool
+	// MoveLogsTo is the activity the logs are moved to
+	MoveLogsTo *Activity
+}
+
+// Check returns an error if the logs of the activity would be left without an activity
+func (opts DeleteOptions) Check(activity Activity, hasLogs bool) error {
+	if opts.DeleteLogs && opts.MoveLogsTo != nil {
+		return errors.New("the logs of a deleted activity can either be deleted or moved, not both")
+	}
+
+	if opts.MoveLogsTo != nil && opts.MoveLogsTo.Id == activity.Id {
+		return errors.New("the logs of a deleted activity cannot be moved to the activity itself")
+	}
+
+	if hasLogs && !opts.DeleteLogs && opts.MoveLogsTo == nil {
+		return fmt.Errorf("activity '%s' has tracked sessions, please choose whether to delete them or to move them to another activity, or archive the activity instead", activity.Name)
+	}
+
+	return nil
 }
 
 type UpdateActivity interface {
@@ -47,6 +82,24 @@ func (upd UpdateActivityNameAndDescription) Visit(act *Activity) {
 	updDesc.Visit(act)
 }
 
+// UpdateActivityConcurrent sets whether the activity can be tracked at the same time as other activities
+type UpdateActivityConcurrent struct {
+	Concurrent bool
+}
+
+func (upd UpdateActivityConcurrent) Visit(act *Activity) {
+	act.Concurrent = upd.Concurrent
+}
+
+// UpdateActivityFields applies several updates, in order
+type UpdateActivityFields []UpdateActivity
+
+func (upd UpdateActivityFields) Visit(act *Activity) {
+	for _, update := range upd {
+		update.Visit(act)
+	}
+}
+
 type NoActivityUpdate struct{}
 
 func (upd NoActivityUpdate) Visit(act *Activity) {
@@ -63,7 +116,17 @@ func (activity *Activity) HasDescription() bool {
 	return activity.Description != ""
 }
 
-// ValidateName validates the correctness of the activity name.
+// CanRunAlongside returns true if sessions of the activity can be tracked at the same time as sessions of the other one,
+// which is the case if either of them is concurrent, or if concurrent timers are enabled for all activities.
+// An activity never runs alongside itself.
+func (activity *Activity) CanRunAlongside(other Activity, concurrentTimers bool) bool {
+	if activity.Id == other.Id {
+		return false
+	}
+	return concurrentTimers || activity.Concurrent || other.Concurrent
+}
+
+// ValidateName validates the correctness of the activity name. The name of a sub-activity is validated on each of its parts.
 func (activity *Activity) ValidateName() error {
 
 	if activity.Name == "" {
@@ -74,14 +137,64 @@ func (activity *Activity) ValidateName() error {
 		return errors.New("Activity name is not valid: 'index' is a reserved keyword and cannot be used as name")
 	}
 
-	re := regexp.MustCompile(`^[0-9a-zA-Z_-]*$`)
-	if !re.MatchString(activity.Name) {
-		return errors.New("Activity name is not valid. It must only contain alpha numeric characters")
+	re := regexp.MustCompile(`^[0-9a-zA-Z_-]+$`)
+	for _, part := range strings.Split(activity.Name, ActivitySeparator) {
+		if !re.MatchString(part) {
+			return errors.New("Activity name is not valid. It must only contain alpha numeric characters, with '/' separating sub-activities from their parent")
+		}
 	}
 	activity.Name = strings.ToLower(activity.Name)
 	return nil
 }
 
+// ParentName returns the name of the parent of the activity, or an empty string if it is a top level activity
+func (activity *Activity) ParentName() string {
+	return ParentActivityName(activity.Name)
+}
+
+// ParentActivityName returns the name of the parent of the activity with the given name, or an empty string if it has none
+func ParentActivityName(name string) string {
+	i := strings.LastIndex(name, ActivitySeparator)
+	if i < 0 {
+		return ""
+	}
+	return name[:i]
+}
+
+// AncestorActivityNames returns the names of all the parents of the activity with the given name, starting at the top level
+func AncestorActivityNames(name string) []string {
+	var ancestors []string
+	for parent := ParentActivityName(name); parent != ""; parent = ParentActivityName(parent) {
+		ancestors = append([]string{parent}, ancestors...)
+	}
+	return ancestors
+}
+
+// ValidateRename checks that the activity can be renamed to the given name, which must not place it under itself
+func (activity *Activity) ValidateRename(newName string) error {
+	renamed := Activity{Name: newName}
+	if renamed.IsDescendantOf(activity.Name) {
+		return fmt.Errorf("activity '%s' cannot become a sub-activity of itself", activity.Name)
+	}
+	return nil
+}
+
+// IsDescendantOf returns true if the activity is a sub-activity, at any level, of the activity with the given name
+func (activity *Activity) IsDescendantOf(name string) bool {
+	return strings.HasPrefix(activity.Name, name+ActivitySeparator)
+}
+
+// RenameAncestor returns the name of the activity after its ancestor, or itself, is renamed from oldName to newName
+func (activity *Activity) RenameAncestor(oldName string, newName string) string {
+	if activity.Name == oldName {
+		return newName
+	}
+	if activity.IsDescendantOf(oldName) {
+		return newName + strings.TrimPrefix(activity.Name, oldName)
+	}
+	return activity.Name
+}
+
 // ToPrintableString returns a pretty string with the activity data
 func (activity *Activity) ToPrintableString() string {
 	format := "Name: %s"
@@ -96,5 +209,17 @@ func (activity *Activity) ToPrintableString() string {
 		res = fmt.Sprintf("%s\n Description: %s", res, activity.Description)
 	}
 
+	if len(activity.Tags) > 0 {
+		res = fmt.Sprintf("%s\n Tags: %s", res, FormatTags(activity.Tags))
+	}
+
+	if activity.Archived {
+		res = fmt.Sprintf("%s\n Archived", res)
+	}
+
+	if activity.Concurrent {
+		res = fmt.Sprintf("%s\n Concurrent", res)
+	}
+
 	return res
 }
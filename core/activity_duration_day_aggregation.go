@@ -1,8 +1,207 @@
 package core
 
+import (
+	"sort"
+	"time"
+
+	"github.com/luispcosta/go-tt/utils"
+)
+
 // ActivityDurationDayAggregation represents the total duration of an activity for a given date.
 type ActivityDurationDayAggregation struct {
 	Activity Activity
 	Date     string
 	Duration int
+	Notes    []string
+	// TagDurations is the part of the duration, in seconds, spent in sessions with each tag
+	TagDurations map[string]int
+	// LongestSession is the duration, in seconds, of the longest session aggregated, its time outside of the date included
+	LongestSession int
+}
+
+// AggregateLogsByDay sums the duration of the logs per day and activity, keeping only the time inside the period.
+// Days are calendar days in the given time zone, and a log spanning several days has its duration apportioned
+// to each of the days it covers. Logs partially inside an exact period are clipped to it. Paused time is not counted, and logs still running are ignored.
+// Only the logs selected by the filter are summed. Time during which several logs were running is counted fully in each
+// of them, or split evenly between them with SplitOverlap, whether the other logs are selected or not, so that the time
+// of a log does not depend on the filter. The notes and tags of the logs are kept along with the days and activities
+// they contributed to.
+func AggregateLogsByDay(logs []ActivityLog, period Period, loc *time.Location, filter LogFilter) map[string][]ActivityDurationDayAggregation {
+	sorted := make([]ActivityLog, 0, len(logs))
+	for _, log := range logs {
+		if log.StartedAt != nil && log.StoppedAt != nil {
+			sorted = append(sorted, log)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartedAt.Before(*sorted[j].StartedAt)
+	})
+
+	type key struct {
+		date       string
+		activityId int
+	}
+	durations := make(map[key]time.Duration)
+	notes := make(map[key][]string)
+	tagDurations := make(map[key]map[string]time.Duration)
+	longestSessions := make(map[key]time.Duration)
+	var order []key
+	activities := make(map[int]Activity)
+
+	var allIntervals []Interval
+	if filter.Overlap == SplitOverlap {
+		for _, log := range sorted {
+			allIntervals = append(allIntervals, log.WorkIntervals(*log.StoppedAt)...)
+		}
+	}
+
+	period = period.Anchored(loc)
+	for _, log := range sorted {
+		if !filter.Matches(log) {
+			continue
+		}
+
+		activities[log.Activity.Id] = log.Activity
+		for _, interval := range log.WorkIntervals(*log.StoppedAt) {
+			interval, ok := period.Intersect(interval)
+			if !ok {
+				continue
+			}
+
+			for _, piece := range sharedPieces(interval, allIntervals) {
+				sessions := piece.sessions
+				forEachDayOfInterval(piece.Start.In(loc), piece.End.In(loc), func(date string, duration time.Duration) {
+					duration /= time.Duration(sessions)
+					k := key{date: date, activityId: log.Activity.Id}
+					if _, ok := durations[k]; !ok {
+						order = append(order, k)
+						tagDurations[k] = make(map[string]time.Duration)
+					}
+					durations[k] += duration
+					if elapsed := log.Elapsed(*log.StoppedAt); elapsed > longestSessions[k] {
+						longestSessions[k] = elapsed
+					}
+					for _, tag := range log.AllTags() {
+						tagDurations[k][tag] += duration
+					}
+					if log.Note != "" && !containsString(notes[k], log.Note) {
+						notes[k] = append(notes[k], log.Note)
+					}
+				})
+			}
+		}
+	}
+
+	result := make(map[string][]ActivityDurationDayAggregation)
+	for _, k := range order {
+		tagSeconds := make(map[string]int)
+		for tag, duration := range tagDurations[k] {
+			tagSeconds[tag] = toSeconds(duration)
+		}
+		result[k.date] = append(result[k.date], ActivityDurationDayAggregation{
+			Activity:       activities[k.activityId],
+			Date:           k.date,
+			Duration:       toSeconds(durations[k]),
+			Notes:          notes[k],
+			TagDurations:   tagSeconds,
+			LongestSession: toSeconds(longestSessions[k]),
+		})
+	}
+
+	return result
+}
+
+// Tags returns the tags of the sessions aggregated, sorted
+func (aggregation *ActivityDurationDayAggregation) Tags() []string {
+	tags := make([]string, 0, len(aggregation.TagDurations))
+	for tag := range aggregation.TagDurations {
+		tags = append(tags, tag)
+	}
+	return MergeTags(tags)
+}
+
+// TagTotals sums the durations, in seconds, spent in sessions with each tag over all the aggregations
+func TagTotals(logs map[string][]ActivityDurationDayAggregation) map[string]int {
+	totals := make(map[string]int)
+	for _, aggregations := range logs {
+		for _, aggregation := range aggregations {
+			for tag, duration := range aggregation.TagDurations {
+				totals[tag] += duration
+			}
+		}
+	}
+	return totals
+}
+
+func toSeconds(duration time.Duration) int {
+	return int(duration.Round(time.Second).Seconds())
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedPiece is a part of a work interval during which the same number of sessions were running
+type sharedPiece struct {
+	Interval
+	sessions int
+}
+
+// sharedPieces splits the interval wherever the number of the given intervals overlapping it changes, along with
+// that number. The interval is kept whole, shared by a single session, if no intervals are given.
+func sharedPieces(interval Interval, intervals []Interval) []sharedPiece {
+	if len(intervals) == 0 {
+		return []sharedPiece{{Interval: interval, sessions: 1}}
+	}
+
+	bounds := []time.Time{interval.Start, interval.End}
+	for _, other := range intervals {
+		for _, bound := range []time.Time{other.Start, other.End} {
+			if bound.After(interval.Start) && bound.Before(interval.End) {
+				bounds = append(bounds, bound)
+			}
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].Before(bounds[j])
+	})
+
+	var pieces []sharedPiece
+	for i := 1; i < len(bounds); i++ {
+		piece := Interval{Start: bounds[i-1], End: bounds[i]}
+		if !piece.End.After(piece.Start) {
+			continue
+		}
+
+		sessions := 0
+		for _, other := range intervals {
+			if other.Start.Before(piece.End) && other.End.After(piece.Start) {
+				sessions++
+			}
+		}
+		if sessions == 0 {
+			sessions = 1
+		}
+		pieces = append(pieces, sharedPiece{Interval: piece, sessions: sessions})
+	}
+	return pieces
+}
+
+// forEachDayOfInterval splits the interval at each midnight, and calls fn with the date and the duration of each piece.
+func forEachDayOfInterval(start, end time.Time, fn func(string, time.Duration)) {
+	for start.Before(end) {
+		y, m, d := start.Date()
+		nextMidnight := time.Date(y, m, d+1, 0, 0, 0, 0, start.Location())
+		pieceEnd := end
+		if nextMidnight.Before(end) {
+			pieceEnd = nextMidnight
+		}
+		fn(start.Format(utils.DateFormat), pieceEnd.Sub(start))
+		start = pieceEnd
+	}
 }
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAggregateLogsByDaySumsDurationsPerActivity(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	reading := Activity{Id: 2, Name: "reading"}
+	logs := []ActivityLog{
+		newTestLog(coding, time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC), time.Hour),
+		newTestLog(reading, time.Date(2020, 10, 10, 10, 0, 0, 0, time.UTC), time.Minute),
+		newTestLog(coding, time.Date(2020, 10, 10, 11, 0, 0, 0, time.UTC), time.Hour),
+	}
+
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	result := AggregateLogsByDay(logs, period, time.UTC, LogFilter{})
+
+	day := result["2020-10-10"]
+	if len(day) != 2 {
+		t.Fatalf("Should have aggregated 2 activities, got %d", len(day))
+	}
+
+	if day[0].Activity.Name != "coding" || day[0].Duration != 7200 {
+		t.Errorf("Wrong aggregation for coding: %+v", day[0])
+	}
+
+	if day[1].Activity.Name != "reading" || day[1].Duration != 60 {
+		t.Errorf("Wrong aggregation for reading: %+v", day[1])
+	}
+}
+
+func TestAggregateLogsByDaySplitsLogsAtMidnight(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	logs := []ActivityLog{
+		newTestLog(coding, time.Date(2020, 10, 10, 23, 30, 0, 0, time.UTC), time.Hour),
+	}
+
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-11")
+	result := AggregateLogsByDay(logs, period, time.UTC, LogFilter{})
+
+	if result["2020-10-10"][0].Duration != 1800 {
+		t.Errorf("Should have 30 minutes on the first day, got %+v", result["2020-10-10"])
+	}
+
+	if result["2020-10-11"][0].Duration != 1800 {
+		t.Errorf("Should have 30 minutes on the second day, got %+v", result["2020-10-11"])
+	}
+
+	if result["2020-10-10"][0].LongestSession != 3600 || result["2020-10-11"][0].LongestSession != 3600 {
+		t.Errorf("Both days should keep the whole duration of the longest session, got %+v", result)
+	}
+}
+
+func TestAggregateLogsByDayIgnoresDaysOutsidePeriodAndRunningLogs(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	startedAt := time.Date(2020, 10, 11, 9, 0, 0, 0, time.UTC)
+	logs := []ActivityLog{
+		newTestLog(coding, time.Date(2020, 10, 10, 23, 30, 0, 0, time.UTC), time.Hour),
+		{Id: 2, StartedAt: &startedAt, Activity: coding},
+	}
+
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	result := AggregateLogsByDay(logs, period, time.UTC, LogFilter{})
+
+	if len(result) != 1 || result["2020-10-10"][0].Duration != 1800 {
+		t.Errorf("Should only have the first 30 minutes of the first day, got %+v", result)
+	}
+}
+
+func newTestLog(activity Activity, startedAt time.Time, duration time.Duration) ActivityLog {
+	stoppedAt := startedAt.Add(duration)
+	return ActivityLog{StartedAt: &startedAt, StoppedAt: &stoppedAt, Activity: activity}
+}
+
+func TestAggregateLogsByDayUsesTheGivenTimeZone(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	lisbon := time.FixedZone("WEST", 3600)
+	logs := []ActivityLog{
+		newTestLog(coding, time.Date(2020, 10, 10, 23, 0, 0, 0, time.UTC), time.Hour),
+	}
+
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-11")
+	result := AggregateLogsByDay(logs, period, lisbon, LogFilter{})
+
+	if len(result) != 1 || result["2020-10-11"][0].Duration != 3600 {
+		t.Errorf("Should have the whole hour on the next day in UTC+1, got %+v", result)
+	}
+}
+
+func TestAggregateLogsByDayKeepsDurationsPerTag(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding", Tags: []string{"work"}}
+	billable := newTestLog(coding, time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC), time.Hour)
+	billable.Tags = []string{"billable"}
+	logs := []ActivityLog{
+		billable,
+		newTestLog(coding, time.Date(2020, 10, 10, 11, 0, 0, 0, time.UTC), 30*time.Minute),
+	}
+
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	result := AggregateLogsByDay(logs, period, time.UTC, LogFilter{})
+
+	expected := map[string]int{"work": 5400, "billable": 3600}
+	if !reflect.DeepEqual(result["2020-10-10"][0].TagDurations, expected) {
+		t.Errorf("Wrong durations per tag: %v", result["2020-10-10"][0].TagDurations)
+	}
+
+	if !reflect.DeepEqual(TagTotals(result), expected) {
+		t.Errorf("Wrong tag totals: %v", TagTotals(result))
+	}
+}
+
+func TestAggregateLogsByDayCountsOrSplitsOverlappingTime(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	onCall := Activity{Id: 2, Name: "on-call", Concurrent: true}
+	logs := []ActivityLog{
+		newTestLog(onCall, time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC), 4*time.Hour),
+		newTestLog(coding, time.Date(2020, 10, 10, 10, 0, 0, 0, time.UTC), 2*time.Hour),
+	}
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-10")
+
+	durations := func(overlap OverlapMode) map[string]int {
+		result := make(map[string]int)
+		for _, aggregation := range AggregateLogsByDay(logs, period, time.UTC, LogFilter{Overlap: overlap})["2020-10-10"] {
+			result[aggregation.Activity.Name] = aggregation.Duration
+		}
+		return result
+	}
+
+	if full := durations(FullOverlap); full["on-call"] != 4*3600 || full["coding"] != 2*3600 {
+		t.Errorf("Should count the overlapping time fully in each session, got %v", full)
+	}
+
+	if split := durations(SplitOverlap); split["on-call"] != 3*3600 || split["coding"] != 3600 {
+		t.Errorf("Should split the overlapping time between the sessions, got %v", split)
+	}
+}
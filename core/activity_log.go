@@ -1,7 +1,11 @@
 package core
 
 import (
+	"fmt"
+	"sort"
 	"time"
+
+	"github.com/luispcosta/go-tt/utils"
 )
 
 // ActivityLog represents one run of an activity in a given point in time
@@ -11,4 +15,210 @@ type ActivityLog struct {
 	StartedAt *time.Time
 	StoppedAt *time.Time
 	Activity  Activity
+	Pauses    []Pause
+	Note      string
+	// PlannedEnd is the instant the session is considered stopped at, if it was started for a limited time
+	PlannedEnd *time.Time
+	// Tags of the session itself, without the tags of its activity
+	Tags []string
+}
+
+// Pause represents a break inside a run of an activity. A pause not resumed yet has no ResumedAt.
+type Pause struct {
+	Id        int
+	PausedAt  *time.Time
+	ResumedAt *time.Time
+}
+
+// Interval represents a continuous span of time
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// In returns the same log with its instants expressed in the given time zone
+func (log ActivityLog) In(loc *time.Location) ActivityLog {
+	in := func(instant *time.Time) *time.Time {
+		if instant == nil {
+			return nil
+		}
+		converted := instant.In(loc)
+		return &converted
+	}
+
+	log.StartedAt = in(log.StartedAt)
+	log.StoppedAt = in(log.StoppedAt)
+	log.PlannedEnd = in(log.PlannedEnd)
+	pauses := make([]Pause, len(log.Pauses))
+	for i, pause := range log.Pauses {
+		pauses[i] = Pause{Id: pause.Id, PausedAt: in(pause.PausedAt), ResumedAt: in(pause.ResumedAt)}
+	}
+	log.Pauses = pauses
+	return log
+}
+
+// IsRunning returns true if the log was started and not stopped yet
+func (log *ActivityLog) IsRunning() bool {
+	return log.StartedAt != nil && log.StoppedAt == nil
+}
+
+// LogOfActivity returns the first of the logs that is of the activity with the given id, if any
+func LogOfActivity(logs []ActivityLog, activityId int) *ActivityLog {
+	for i := range logs {
+		if logs[i].Activity.Id == activityId {
+			return &logs[i]
+		}
+	}
+	return nil
+}
+
+// IsOverdue returns true if the log is running past its planned end, so it should be stopped at that end
+func (log *ActivityLog) IsOverdue(now time.Time) bool {
+	return log.IsRunning() && log.PlannedEnd != nil && !log.PlannedEnd.After(now)
+}
+
+// SettleOverdueLogs returns copies of the logs where the ones running past their planned end are stopped at that end,
+// as they are once stopped for good, so that reads can show them without writing anything
+func SettleOverdueLogs(logs []ActivityLog, now time.Time) []ActivityLog {
+	settled := append([]ActivityLog(nil), logs...)
+	for i := range settled {
+		if settled[i].IsOverdue(now) {
+			settled[i].StoppedAt = settled[i].PlannedEnd
+		}
+	}
+	return settled
+}
+
+// Remaining returns the time left until the planned end of the log, if it has one
+func (log *ActivityLog) Remaining(now time.Time) (time.Duration, bool) {
+	if log.PlannedEnd == nil {
+		return 0, false
+	}
+	return log.PlannedEnd.Sub(now), true
+}
+
+// IsPaused returns true if the log is running and currently in a pause
+func (log *ActivityLog) IsPaused() bool {
+	return log.IsRunning() && log.OpenPause() != nil
+}
+
+// OpenPause returns the pause not resumed yet, if any
+func (log *ActivityLog) OpenPause() *Pause {
+	for i := range log.Pauses {
+		if log.Pauses[i].ResumedAt == nil {
+			return &log.Pauses[i]
+		}
+	}
+	return nil
+}
+
+// AllTags returns the tags of the log along with the tags of its activity
+func (log *ActivityLog) AllTags() []string {
+	return MergeTags(log.Activity.Tags, log.Tags)
+}
+
+// WorkIntervals returns the intervals in which the activity was actually being done, that is, the time between
+// the start and the stop of the log without its pauses. Logs still running are considered until the given instant.
+func (log *ActivityLog) WorkIntervals(now time.Time) []Interval {
+	if log.StartedAt == nil {
+		return nil
+	}
+
+	end := now
+	if log.StoppedAt != nil {
+		end = *log.StoppedAt
+	}
+
+	var intervals []Interval
+	start := *log.StartedAt
+	for _, pause := range log.sortedPauses() {
+		pauseEnd := end
+		if pause.ResumedAt != nil && pause.ResumedAt.Before(end) {
+			pauseEnd = *pause.ResumedAt
+		}
+		if pause.PausedAt.After(start) {
+			intervals = append(intervals, Interval{Start: start, End: minTime(*pause.PausedAt, end)})
+		}
+		if pauseEnd.After(start) {
+			start = pauseEnd
+		}
+	}
+
+	if end.After(start) {
+		intervals = append(intervals, Interval{Start: start, End: end})
+	}
+
+	return intervals
+}
+
+// Overlaps returns true if the log shares some time with the interval. Logs still running are considered until the given instant.
+func (log *ActivityLog) Overlaps(interval Interval, now time.Time) bool {
+	if log.StartedAt == nil {
+		return false
+	}
+
+	end := now
+	if log.StoppedAt != nil {
+		end = *log.StoppedAt
+	}
+
+	return log.StartedAt.Before(interval.End) && end.After(interval.Start)
+}
+
+// Describe returns when the log took place, as in "from 2020-10-10 09:00 to 10:00"
+func (log *ActivityLog) Describe() string {
+	if log.StartedAt == nil {
+		return "not started"
+	}
+
+	from := log.StartedAt.Format("2006-01-02 15:04")
+	if log.StoppedAt == nil {
+		return fmt.Sprintf("started at %s and still running", from)
+	}
+
+	to := log.StoppedAt.Format("15:04")
+	if log.StoppedAt.Format(utils.DateFormat) != log.StartedAt.Format(utils.DateFormat) {
+		to = log.StoppedAt.Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("from %s to %s", from, to)
+}
+
+// Elapsed returns the time spent on the activity, without pauses. Logs still running are considered until the given instant.
+func (log *ActivityLog) Elapsed(now time.Time) time.Duration {
+	var elapsed time.Duration
+	for _, interval := range log.WorkIntervals(now) {
+		elapsed += interval.End.Sub(interval.Start)
+	}
+	return elapsed
+}
+
+// ElapsedIn returns the time spent on the activity inside the period, without pauses. Logs still running are considered until the given instant.
+func (log *ActivityLog) ElapsedIn(period Period, now time.Time) time.Duration {
+	var elapsed time.Duration
+	for _, interval := range log.WorkIntervals(now) {
+		if inside, ok := period.Intersect(interval); ok {
+			elapsed += inside.End.Sub(inside.Start)
+		}
+	}
+	return elapsed
+}
+
+func (log *ActivityLog) sortedPauses() []Pause {
+	sorted := make([]Pause, 0, len(log.Pauses))
+	for _, pause := range log.Pauses {
+		if pause.PausedAt != nil {
+			sorted = append(sorted, pause)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].PausedAt.Before(*sorted[j].PausedAt)
+	})
+	return sorted
+}
+
+func minTime(t1, t2 time.Time) time.Time {
+	if t1.Before(t2) {
+		return t1
+	}
+	return t2
 }
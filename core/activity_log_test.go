@@ -0,0 +1,113 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestElapsedWithoutPauses(t *testing.T) {
+	log := newTestLog(Activity{Id: 1}, time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC), time.Hour)
+
+	if log.Elapsed(time.Now()) != time.Hour {
+		t.Errorf("Should have elapsed one hour, got %v", log.Elapsed(time.Now()))
+	}
+}
+
+func TestElapsedSubtractsPauses(t *testing.T) {
+	startedAt := time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC)
+	log := newTestLog(Activity{Id: 1}, startedAt, 2*time.Hour)
+	log.Pauses = []Pause{
+		newTestPause(startedAt.Add(90*time.Minute), 10*time.Minute),
+		newTestPause(startedAt.Add(30*time.Minute), 20*time.Minute),
+	}
+
+	if log.Elapsed(time.Now()) != 90*time.Minute {
+		t.Errorf("Should have elapsed 90 minutes, got %v", log.Elapsed(time.Now()))
+	}
+
+	intervals := log.WorkIntervals(time.Now())
+	if len(intervals) != 3 {
+		t.Fatalf("Should have 3 work intervals, got %+v", intervals)
+	}
+
+	if !intervals[1].Start.Equal(startedAt.Add(50*time.Minute)) || !intervals[1].End.Equal(startedAt.Add(90*time.Minute)) {
+		t.Errorf("Wrong work interval between pauses: %+v", intervals[1])
+	}
+}
+
+func TestElapsedOfRunningPausedLog(t *testing.T) {
+	startedAt := time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC)
+	pausedAt := startedAt.Add(45 * time.Minute)
+	log := ActivityLog{StartedAt: &startedAt, Pauses: []Pause{{PausedAt: &pausedAt}}}
+
+	if !log.IsPaused() {
+		t.Error("Log should be paused")
+	}
+
+	now := startedAt.Add(3 * time.Hour)
+	if log.Elapsed(now) != 45*time.Minute {
+		t.Errorf("Should have elapsed 45 minutes while paused, got %v", log.Elapsed(now))
+	}
+}
+
+func TestElapsedInPeriodOfRunningLogStartedTheDayBefore(t *testing.T) {
+	startedAt := time.Date(2020, 10, 9, 23, 0, 0, 0, time.UTC)
+	pausedAt := time.Date(2020, 10, 10, 1, 0, 0, 0, time.UTC)
+	log := ActivityLog{StartedAt: &startedAt, Pauses: []Pause{newTestPause(pausedAt, 30*time.Minute)}}
+	today := Period{Sd: time.Date(2020, 10, 10, 0, 0, 0, 0, time.UTC), Ed: time.Date(2020, 10, 10, 0, 0, 0, 0, time.UTC)}
+
+	now := time.Date(2020, 10, 10, 2, 0, 0, 0, time.UTC)
+	if log.ElapsedIn(today, now) != 90*time.Minute {
+		t.Errorf("Should have elapsed 90 minutes today, got %v", log.ElapsedIn(today, now))
+	}
+}
+
+func newTestPause(pausedAt time.Time, duration time.Duration) Pause {
+	resumedAt := pausedAt.Add(duration)
+	return Pause{PausedAt: &pausedAt, ResumedAt: &resumedAt}
+}
+
+func TestRunningLogIsOverdueAfterItsPlannedEnd(t *testing.T) {
+	startedAt := time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC)
+	plannedEnd := startedAt.Add(45 * time.Minute)
+	log := ActivityLog{StartedAt: &startedAt, PlannedEnd: &plannedEnd}
+
+	if log.IsOverdue(startedAt.Add(30 * time.Minute)) {
+		t.Error("Should not be overdue before its planned end")
+	}
+
+	if remaining, ok := log.Remaining(startedAt.Add(30 * time.Minute)); !ok || remaining != 15*time.Minute {
+		t.Errorf("Should have 15 minutes left, got %v", remaining)
+	}
+
+	if !log.IsOverdue(plannedEnd) {
+		t.Error("Should be overdue at its planned end")
+	}
+
+	log.StoppedAt = &plannedEnd
+	if log.IsOverdue(startedAt.Add(time.Hour)) {
+		t.Error("A stopped log should not be overdue")
+	}
+}
+
+func TestSettleOverdueLogsStopsThemAtTheirPlannedEndOnACopy(t *testing.T) {
+	startedAt := time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC)
+	plannedEnd := startedAt.Add(45 * time.Minute)
+	logs := []ActivityLog{
+		{Id: 1, StartedAt: &startedAt, PlannedEnd: &plannedEnd},
+		{Id: 2, StartedAt: &startedAt},
+	}
+
+	settled := SettleOverdueLogs(logs, startedAt.Add(time.Hour))
+	if settled[0].StoppedAt == nil || !settled[0].StoppedAt.Equal(plannedEnd) {
+		t.Errorf("Should have stopped the overdue log at its planned end, got %v", settled[0].StoppedAt)
+	}
+
+	if settled[1].StoppedAt != nil {
+		t.Error("Should have kept the log without a planned end running")
+	}
+
+	if logs[0].StoppedAt != nil {
+		t.Error("Should not have changed the given logs")
+	}
+}
@@ -1,20 +1,44 @@
 package core
 
-import "github.com/luispcosta/go-tt/config"
+import (
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+)
 
 // ActivityRepository is the generic interface that exposes methods to create and read activities from a store.
 type ActivityRepository interface {
 	Initialize(config.Config) error
 	Shutdown() error
 	Add(Activity) error
-	Delete(string) error
+	Delete(string, DeleteOptions) error
+	Archive(string) error
+	Unarchive(string) error
 	List() ([]Activity, error)
 	Update(string, UpdateActivity) error
 	Find(string) (*Activity, error)
-	Start(Activity) error
-	LogsForPeriod(Period) (map[string][]ActivityDurationDayAggregation, error)
-	Stop(Activity) error
+	Start(Activity, StartOptions) error
+	LogsForPeriod(Period, LogFilter) (map[string][]ActivityDurationDayAggregation, error)
+	Stop(Activity, StopOptions) error
+	Switch(Activity, StartOptions) error
+	LogSession(Activity, Interval, StartOptions) error
+	SessionsForPeriod(Period, *Activity) ([]ActivityLog, error)
+	FindSession(int) (*ActivityLog, error)
+	UpdateSession(int, SessionUpdate) error
+	DeleteSession(int) error
+	ApplySessionChanges(SessionChanges) error
 	CurrentlyTrackedActivity() (*Activity, error)
+	CurrentSession() (*ActivityLog, error)
+	CurrentSessions() ([]ActivityLog, error)
+	CurrentActivityLogs(time.Time) ([]ActivityLog, error)
+	LastSession() (*ActivityLog, error)
+	SetNote(int, string) error
+	TagActivity(Activity, []string) error
+	UntagActivity(Activity, []string) error
+	Tags() ([]string, error)
+	RenameTag(string, string) error
+	Pause() error
+	Resume() error
 	WipeLogsPeriodAndActivity(Period, *Activity) error
 	WipeLogsPeriod(Period) error
 }
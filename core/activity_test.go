@@ -83,3 +83,47 @@ func TestValidNameHasWithCorrectChars(t *testing.T) {
 		t.Fatal("Should not have failed with correct name chars")
 	}
 }
+
+func TestValidateNameOfSubActivity(t *testing.T) {
+	activity := Activity{Name: "Work/Backend/reviews"}
+
+	err := activity.ValidateName()
+
+	if err != nil || activity.Name != "work/backend/reviews" {
+		t.Fatalf("Should have accepted the sub-activity name, got %s (%v)", activity.Name, err)
+	}
+}
+
+func TestValidateNameOfSubActivityWithEmptyPart(t *testing.T) {
+	for _, name := range []string{"/work", "work//backend"} {
+		activity := Activity{Name: name}
+
+		if activity.ValidateName() == nil {
+			t.Errorf("Should have failed with empty part in name %s", name)
+		}
+	}
+}
+
+func TestAncestorActivityNames(t *testing.T) {
+	ancestors := AncestorActivityNames("work/backend/reviews")
+
+	if len(ancestors) != 2 || ancestors[0] != "work" || ancestors[1] != "work/backend" {
+		t.Errorf("Wrong ancestors: %v", ancestors)
+	}
+
+	if len(AncestorActivityNames("work")) != 0 {
+		t.Error("Top level activity should have no ancestors")
+	}
+}
+
+func TestRenameAncestor(t *testing.T) {
+	activity := Activity{Name: "work/backend/reviews"}
+
+	if renamed := activity.RenameAncestor("work/backend", "job/api"); renamed != "job/api/reviews" {
+		t.Errorf("Wrong renamed name: %s", renamed)
+	}
+
+	if renamed := activity.RenameAncestor("work/back", "job"); renamed != "work/backend/reviews" {
+		t.Errorf("Should not rename when the old name is not an ancestor, got %s", renamed)
+	}
+}
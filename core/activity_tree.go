@@ -0,0 +1,88 @@
+package core
+
+import "strings"
+
+// ActivityTreeNode is an activity in the tree of activities of a report, with the time spent on the activity itself
+// and the time rolled up from its sub-activities.
+type ActivityTreeNode struct {
+	// Name is the full name of the activity
+	Name string
+	// Own is the aggregation of the sessions of the activity itself. Its duration is zero if the activity
+	// only has time through its sub-activities.
+	Own ActivityDurationDayAggregation
+	// Duration is the time, in seconds, spent on the activity and all its sub-activities
+	Duration int
+	// LongestSession is the duration, in seconds, of the longest session of the activity and all its sub-activities
+	LongestSession int
+	Children       []*ActivityTreeNode
+}
+
+// ShortName returns the name of the activity without the name of its parents
+func (node *ActivityTreeNode) ShortName() string {
+	return node.Name[strings.LastIndex(node.Name, ActivitySeparator)+1:]
+}
+
+// HasSubActivityTime returns true if some of the time of the node was spent on its sub-activities
+func (node *ActivityTreeNode) HasSubActivityTime() bool {
+	return node.Duration != node.Own.Duration
+}
+
+// BuildActivityTree arranges the aggregations of a day in a tree following the hierarchy of their activities.
+// Parents without time of their own are part of the tree, so that their sub-activities time is rolled up in them.
+// Nodes and their children keep the order of the aggregations. A positive depth collapses the tree: the nodes at that
+// depth keep the rolled up time of their sub-activities, which are left out.
+func BuildActivityTree(aggregations []ActivityDurationDayAggregation, depth int) []*ActivityTreeNode {
+	var roots []*ActivityTreeNode
+	nodes := make(map[string]*ActivityTreeNode)
+
+	var nodeFor func(name string) *ActivityTreeNode
+	nodeFor = func(name string) *ActivityTreeNode {
+		if node, ok := nodes[name]; ok {
+			return node
+		}
+
+		node := &ActivityTreeNode{Name: name, Own: ActivityDurationDayAggregation{Activity: Activity{Name: name}}}
+		nodes[name] = node
+		parentName := ParentActivityName(name)
+		if parentName == "" {
+			roots = append(roots, node)
+		} else {
+			parent := nodeFor(parentName)
+			parent.Children = append(parent.Children, node)
+		}
+		return node
+	}
+
+	for _, aggregation := range aggregations {
+		node := nodeFor(aggregation.Activity.Name)
+		node.Own = aggregation
+		for name := aggregation.Activity.Name; name != ""; name = ParentActivityName(name) {
+			nodes[name].Duration += aggregation.Duration
+			if aggregation.LongestSession > nodes[name].LongestSession {
+				nodes[name].LongestSession = aggregation.LongestSession
+			}
+		}
+	}
+
+	if depth > 0 {
+		WalkActivityTree(roots, func(node *ActivityTreeNode, level int) {
+			if level == depth-1 {
+				node.Children = nil
+			}
+		})
+	}
+
+	return roots
+}
+
+// WalkActivityTree calls fn on each node of the tree, parents before their children, with the depth of the node starting at 0
+func WalkActivityTree(nodes []*ActivityTreeNode, fn func(*ActivityTreeNode, int)) {
+	walkActivityTree(nodes, 0, fn)
+}
+
+func walkActivityTree(nodes []*ActivityTreeNode, level int, fn func(*ActivityTreeNode, int)) {
+	for _, node := range nodes {
+		fn(node, level)
+		walkActivityTree(node.Children, level+1, fn)
+	}
+}
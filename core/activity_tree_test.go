@@ -0,0 +1,75 @@
+package core
+
+import "testing"
+
+func newTestAggregation(name string, duration int) ActivityDurationDayAggregation {
+	return ActivityDurationDayAggregation{Activity: Activity{Name: name}, Date: "2020-10-10", Duration: duration}
+}
+
+func TestBuildActivityTreeRollsUpSubActivitiesTime(t *testing.T) {
+	aggregations := []ActivityDurationDayAggregation{
+		newTestAggregation("work/backend/reviews", 600),
+		newTestAggregation("reading", 60),
+		newTestAggregation("work", 300),
+		newTestAggregation("work/frontend", 120),
+	}
+
+	roots := BuildActivityTree(aggregations, 0)
+
+	if len(roots) != 2 || roots[0].Name != "work" || roots[1].Name != "reading" {
+		t.Fatalf("Wrong roots: %+v", roots)
+	}
+
+	work := roots[0]
+	if work.Duration != 1020 || work.Own.Duration != 300 || !work.HasSubActivityTime() {
+		t.Errorf("Wrong durations for work: %d (own %d)", work.Duration, work.Own.Duration)
+	}
+
+	if len(work.Children) != 2 || work.Children[0].ShortName() != "backend" || work.Children[1].ShortName() != "frontend" {
+		t.Fatalf("Wrong children for work: %+v", work.Children)
+	}
+
+	backend := work.Children[0]
+	if backend.Duration != 600 || backend.Own.Duration != 0 {
+		t.Errorf("Parent without time of its own should only have rolled up time: %d (own %d)", backend.Duration, backend.Own.Duration)
+	}
+
+	if roots[1].HasSubActivityTime() {
+		t.Error("Activity without sub-activities should only have time of its own")
+	}
+}
+
+func TestBuildActivityTreeCollapsesToDepth(t *testing.T) {
+	aggregations := []ActivityDurationDayAggregation{
+		newTestAggregation("work/backend/reviews", 600),
+		newTestAggregation("work/backend", 60),
+	}
+
+	roots := BuildActivityTree(aggregations, 2)
+
+	var names []string
+	WalkActivityTree(roots, func(node *ActivityTreeNode, level int) {
+		names = append(names, node.Name)
+	})
+
+	if len(names) != 2 || names[0] != "work" || names[1] != "work/backend" {
+		t.Fatalf("Should have collapsed the tree at depth 2, got %v", names)
+	}
+
+	if roots[0].Children[0].Duration != 660 {
+		t.Errorf("Collapsed node should keep the time of its sub-activities, got %d", roots[0].Children[0].Duration)
+	}
+}
+
+func TestBuildActivityTreeRollsUpLongestSession(t *testing.T) {
+	reviews := newTestAggregation("work/backend/reviews", 600)
+	reviews.LongestSession = 50000
+	backend := newTestAggregation("work/backend", 60)
+	backend.LongestSession = 60
+
+	roots := BuildActivityTree([]ActivityDurationDayAggregation{reviews, backend}, 2)
+
+	if roots[0].LongestSession != 50000 || roots[0].Children[0].LongestSession != 50000 {
+		t.Errorf("Parents should keep the longest session of their sub-activities, got %d and %d", roots[0].LongestSession, roots[0].Children[0].LongestSession)
+	}
+}
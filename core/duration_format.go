@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/luispcosta/go-tt/utils"
@@ -16,6 +17,7 @@ const (
 	Seconds = "s"
 	Minutes = "m"
 	Hours   = "r"
+	Clock   = "c"
 )
 
 func ParseDurationFormat(f string) DurationFormat {
@@ -28,6 +30,8 @@ func ParseDurationFormat(f string) DurationFormat {
 		return MinutesDurationFormat{}
 	case Hours:
 		return HoursDurationFormat{}
+	case Clock:
+		return ClockDurationFormat{}
 	default:
 		return HumanDurationFormat{}
 	}
@@ -51,6 +55,13 @@ func (f MinutesDurationFormat) Format(secondsDuration int) string {
 	return strconv.Itoa(secondsDuration / 60)
 }
 
+// ClockDurationFormat prints durations as hours and minutes, as in 1:05
+type ClockDurationFormat struct{}
+
+func (f ClockDurationFormat) Format(secondsDuration int) string {
+	return fmt.Sprintf("%d:%02d", secondsDuration/3600, secondsDuration/60%60)
+}
+
 type HoursDurationFormat struct{}
 
 func (f HoursDurationFormat) Format(secondsDuration int) string {
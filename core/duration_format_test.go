@@ -0,0 +1,12 @@
+package core
+
+import "testing"
+
+func TestClockDurationFormat(t *testing.T) {
+	format := ParseDurationFormat(Clock)
+	for seconds, expected := range map[int]string{0: "0:00", 59: "0:00", 3900: "1:05", 36000 + 59*60: "10:59"} {
+		if formatted := format.Format(seconds); formatted != expected {
+			t.Errorf("%d seconds should be formatted as %s, got %s", seconds, expected, formatted)
+		}
+	}
+}
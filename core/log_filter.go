@@ -0,0 +1,53 @@
+package core
+
+import "fmt"
+
+// OverlapMode is how reports count the time during which several sessions were tracked at once
+type OverlapMode string
+
+const (
+	// FullOverlap counts the overlapping time fully in each of the sessions
+	FullOverlap OverlapMode = "full"
+	// SplitOverlap splits the overlapping time evenly between the sessions
+	SplitOverlap OverlapMode = "split"
+)
+
+// ParseOverlapMode returns the overlap mode with the given name, the full one if it is empty
+func ParseOverlapMode(value string) (OverlapMode, error) {
+	switch OverlapMode(value) {
+	case "", FullOverlap:
+		return FullOverlap, nil
+	case SplitOverlap:
+		return SplitOverlap, nil
+	}
+	return FullOverlap, fmt.Errorf("invalid overlap mode '%s', expected %s or %s", value, FullOverlap, SplitOverlap)
+}
+
+// LogFilter selects the activity logs taken into account by reports, by their tags, and how the time they overlap is counted.
+// The tags of a log are its own tags along with the tags of its activity.
+type LogFilter struct {
+	// Tags the logs must all have. No tags means every log is selected.
+	Tags []string
+	// ExcludedTags the logs must not have any of
+	ExcludedTags []string
+	// Overlap is how the time during which several logs were running, selected or not, is counted, fully if empty
+	Overlap OverlapMode
+}
+
+// Matches returns true if the log is selected by the filter
+func (filter LogFilter) Matches(log ActivityLog) bool {
+	tags := log.AllTags()
+	for _, tag := range filter.Tags {
+		if !hasTag(tags, tag) {
+			return false
+		}
+	}
+
+	for _, tag := range filter.ExcludedTags {
+		if hasTag(tags, tag) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,25 +1,124 @@
 package core
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/luispcosta/go-tt/utils"
 )
 
-// Period represents a time period
+// Period represents a time period. By default, it covers whole calendar days, from the day of Sd to the day of Ed,
+// both included. An exact period covers the time from the instant Sd to the instant Ed instead.
 type Period struct {
-	Sd time.Time
-	Ed time.Time
+	Sd    time.Time
+	Ed    time.Time
+	Exact bool
 }
 
 // NumberOfDays returns the number of days in the period
 func (period *Period) NumberOfDays() int {
-	if dateEqual(period.Sd, period.Ed) {
+	days := daysBetween(period.Sd, period.Ed)
+	if days == 0 {
 		return 1
 	}
 
-	return int(period.Ed.Sub(period.Sd).Hours() / 24)
+	return days
+}
+
+// In returns the same period with its dates expressed in the given time zone
+func (period Period) In(loc *time.Location) Period {
+	return Period{Sd: period.Sd.In(loc), Ed: period.Ed.In(loc), Exact: period.Exact}
+}
+
+// Anchored returns the period with its days taken as calendar days of the given time zone, whatever the time zone
+// its dates were expressed in. Exact periods are left unchanged.
+func (period Period) Anchored(loc *time.Location) Period {
+	if period.Exact {
+		return period
+	}
+	return Period{Sd: startOfDay(period.Sd, loc), Ed: startOfDay(period.Ed, loc)}
+}
+
+// Interval returns the instants the period starts and ends at. The end of a period of whole days is the midnight
+// after its last day.
+func (period Period) Interval() Interval {
+	if period.Exact {
+		return Interval{Start: period.Sd, End: period.Ed}
+	}
+	return Interval{Start: startOfDay(period.Sd, period.Sd.Location()), End: startOfDay(period.Ed, period.Sd.Location()).AddDate(0, 0, 1)}
+}
+
+// Contains returns true if the instant is inside the period
+func (period Period) Contains(instant time.Time) bool {
+	bounds := period.Interval()
+	return !instant.Before(bounds.Start) && instant.Before(bounds.End)
+}
+
+// Overlaps returns true if the period shares some time with the interval
+func (period Period) Overlaps(interval Interval) bool {
+	_, ok := period.Intersect(interval)
+	return ok
+}
+
+// Intersect returns the part of the interval inside the period, and false if there is none
+func (period Period) Intersect(interval Interval) (Interval, bool) {
+	bounds := period.Interval()
+	if interval.Start.After(bounds.Start) {
+		bounds.Start = interval.Start
+	}
+	if interval.End.Before(bounds.End) {
+		bounds.End = interval.End
+	}
+	return bounds, bounds.End.After(bounds.Start)
+}
+
+// SplitByDay returns the parts of the period in each calendar day
+func (period Period) SplitByDay() []Period {
+	return period.split(func(instant time.Time) time.Time {
+		return startOfDay(instant, instant.Location())
+	}, func(start time.Time) time.Time {
+		return start.AddDate(0, 0, 1)
+	})
+}
+
+// SplitByWeek returns the parts of the period in each calendar week, weeks starting on the given day
+func (period Period) SplitByWeek(weekStart time.Weekday) []Period {
+	return period.split(func(instant time.Time) time.Time {
+		day := startOfDay(instant, instant.Location())
+		return day.AddDate(0, 0, -(int(day.Weekday())-int(weekStart)+7)%7)
+	}, func(start time.Time) time.Time {
+		return start.AddDate(0, 0, 7)
+	})
+}
+
+// SplitByMonth returns the parts of the period in each calendar month
+func (period Period) SplitByMonth() []Period {
+	return period.split(func(instant time.Time) time.Time {
+		return time.Date(instant.Year(), instant.Month(), 1, 0, 0, 0, 0, instant.Location())
+	}, func(start time.Time) time.Time {
+		return start.AddDate(0, 1, 0)
+	})
+}
+
+// split returns the parts of the period in each calendar unit, given the start of the unit with an instant and
+// the start of the next unit. Parts of a period of whole days are periods of whole days too.
+func (period Period) split(unitStart func(time.Time) time.Time, nextUnitStart func(time.Time) time.Time) []Period {
+	bounds := period.Interval()
+	var parts []Period
+	for start := unitStart(bounds.Start); start.Before(bounds.End); start = nextUnitStart(start) {
+		part, ok := period.Intersect(Interval{Start: start, End: nextUnitStart(start)})
+		if !ok {
+			continue
+		}
+
+		if period.Exact {
+			parts = append(parts, Period{Sd: part.Start, Ed: part.End, Exact: true})
+		} else {
+			parts = append(parts, Period{Sd: part.Start, Ed: part.End.AddDate(0, 0, -1)})
+		}
+	}
+	return parts
 }
 
 // PeriodFromDateStrings returns a new period struct from two date strings, if they are valid
@@ -46,13 +145,21 @@ const lastMonthPeriod = "month"
 const lastYearPeriod = "year"
 
 // PeriodFromKeyWord returns a fixed period from a representation string, relative to the current date.
-func PeriodFromKeyWord(keyword string) Period {
-	now := time.Now()
-	ed := now
+// Calendar weeks start on Monday. An unknown keyword is an error.
+func PeriodFromKeyWord(keyword string) (Period, error) {
+	period, ok := PeriodParser{Now: time.Now(), WeekStart: time.Monday}.keywordPeriod(strings.ToLower(keyword))
+	if !ok {
+		return Period{}, fmt.Errorf("unknown period '%s', expected one of %v", keyword, AllowedPeriodFixedTimeFrames())
+	}
+	return period, nil
+}
+
+// periodFromKeyWord returns the fixed period with the given lowercase keyword, ending at now
+func periodFromKeyWord(keyword string, now time.Time) (Period, bool) {
 	var sd time.Time
-	switch strings.ToLower(keyword) {
+	switch keyword {
 	case lastDayPeriod:
-		sd = now.AddDate(0, 0, 0)
+		sd = now
 	case lastWeekPeriod:
 		sd = now.AddDate(0, 0, -7)
 	case lastMonthPeriod:
@@ -60,25 +167,17 @@ func PeriodFromKeyWord(keyword string) Period {
 	case lastYearPeriod:
 		sd = now.AddDate(-1, 0, 0)
 	default:
-		sd = now.AddDate(0, 0, -1)
+		return Period{}, false
 	}
 
-	return Period{Sd: sd, Ed: ed}
+	return Period{Sd: sd, Ed: now}, true
 }
 
+// ForEachDay calls fn with the start of each calendar day of the period, the first and last days included.
+// Days are calendar days of the period's time zone, so days shortened or lengthened by DST changes count once.
 func (period *Period) ForEachDay(fn func(time.Time) error) {
-	if period.NumberOfDays() == 1 {
-		fn(period.Sd)
-	} else {
-		i := 0
-		for {
-			nextDay := period.Sd.AddDate(0, 0, i)
-			if nextDay == period.Ed.AddDate(0, 0, 1) {
-				break
-			}
-			fn(nextDay)
-			i += 1
-		}
+	for _, day := range period.SplitByDay() {
+		fn(startOfDay(day.Sd, day.Sd.Location()))
 	}
 }
 
@@ -86,13 +185,22 @@ func (period *Period) StartDateDay() string {
 	return period.Sd.Format(utils.DateFormat)
 }
 
+// EndDateDay returns the last day of the period. The last day of an exact period ending at midnight is the day before.
 func (period *Period) EndDateDay() string {
+	if period.Exact && period.Ed.After(period.Sd) {
+		return period.Ed.Add(-time.Nanosecond).Format(utils.DateFormat)
+	}
 	return period.Ed.Format(utils.DateFormat)
 }
 
-// AllowedPeriodFixedTimeFrames returns an array of allowed period fixed time frames
+// AllowedPeriodFixedTimeFrames returns an array of allowed period fixed time frames: the rolling ones ending today,
+// and the calendar ones
 func AllowedPeriodFixedTimeFrames() []string {
-	return []string{lastDayPeriod, lastWeekPeriod, lastMonthPeriod, lastYearPeriod}
+	frames := []string{lastDayPeriod, lastWeekPeriod, lastMonthPeriod, lastYearPeriod}
+	for _, unit := range []string{weekUnit, monthUnit, quarterUnit, yearUnit} {
+		frames = append(frames, thisPrefix+unit, lastCalendarPrefix+unit)
+	}
+	return frames
 }
 
 func parseSimpleDate(date string) (*time.Time, error) {
@@ -103,8 +211,17 @@ func parseSimpleDate(date string) (*time.Time, error) {
 	return &parsedDate, nil
 }
 
-func dateEqual(date1, date2 time.Time) bool {
+// startOfDay returns the midnight starting the calendar day of the date, in the given time zone
+func startOfDay(date time.Time, loc *time.Location) time.Time {
+	y, m, d := date.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// daysBetween returns the number of calendar days from date1 to date2, ignoring the time of day and DST changes.
+func daysBetween(date1, date2 time.Time) int {
 	y1, m1, d1 := date1.Date()
 	y2, m2, d2 := date2.Date()
-	return y1 == y2 && m1 == m2 && d1 == d2
+	day1 := time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC)
+	return int(day2.Sub(day1).Hours() / 24)
 }
@@ -0,0 +1,278 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/luispcosta/go-tt/utils"
+)
+
+const todayDay = "today"
+const yesterdayDay = "yesterday"
+const sincePrefix = "since "
+const lastPrefix = "last "
+
+const thisPrefix = "this-"
+const lastCalendarPrefix = "last-"
+
+// Calendar units of the periods given as this-<UNIT> or last-<UNIT>
+const weekUnit = "week"
+const monthUnit = "month"
+const quarterUnit = "quarter"
+const yearUnit = "year"
+
+// Patterns of the periods given in ISO notation, as in 2026-W42, 2026-10, 2026-Q3 or 2026
+var isoYearPattern = regexp.MustCompile(`^(\d{4})$`)
+var isoWeekPattern = regexp.MustCompile(`^(\d{4})-w(\d{1,2})$`)
+var isoMonthPattern = regexp.MustCompile(`^(\d{4})-(\d{2})$`)
+var isoQuarterPattern = regexp.MustCompile(`^(\d{4})-q([1-4])$`)
+
+// lastHoursPattern matches an exact period ending now, as in "last 4 hours"
+var lastHoursPattern = regexp.MustCompile(`^last\s+(\d+)\s*(minutes?|mins?|m|hours?|h)$`)
+
+// daysAgoPattern matches a day given relatively to today, as in "3 days ago"
+var daysAgoPattern = regexp.MustCompile(`^(\d+)\s+(days?|weeks?|months?|years?)\s+ago$`)
+
+// PeriodParser reads the periods given in the command line, relatively to the current instant
+type PeriodParser struct {
+	// Now is the current instant, in the time zone used to decide which day a session belongs to
+	Now time.Time
+	// DateFormats are the layouts dates can be given in, on top of 2006-01-02
+	DateFormats []string
+	// WeekStart is the first day of the week of the calendar periods
+	WeekStart time.Weekday
+}
+
+// Parse returns the period given by the arguments:
+// no argument is today, one argument is a fixed time frame (see AllowedPeriodFixedTimeFrames), a calendar period
+// in ISO notation (2026-W42, 2026-10, 2026-Q3 or 2026), a single day, "since <DAY>", or an exact period ending now,
+// as in "since 08:00" or "last 4 hours", and two arguments are the first and last days of the period,
+// or calendar periods it goes from and to.
+// Days are dates, "today", "yesterday", a weekday ("monday", "last monday") or days ago ("3 days ago", "2 weeks ago").
+func (parser PeriodParser) Parse(args ...string) (Period, error) {
+	switch len(args) {
+	case 0:
+		today := parser.today()
+		return Period{Sd: today, Ed: today}, nil
+	case 1:
+		value := normalizedPeriodArgument(args[0])
+		if period, ok := parser.keywordPeriod(value); ok {
+			return period, nil
+		}
+
+		if match := lastHoursPattern.FindStringSubmatch(value); match != nil {
+			amount, _ := strconv.Atoi(match[1])
+			unit := time.Minute
+			if match[2][0] == 'h' {
+				unit = time.Hour
+			}
+			return Period{Sd: parser.Now.Add(-time.Duration(amount) * unit), Ed: parser.Now, Exact: true}, nil
+		}
+
+		if strings.HasPrefix(value, sincePrefix) {
+			sinceValue := strings.TrimPrefix(value, sincePrefix)
+			if instant, err := ParseInstant(sinceValue, parser.Now); err == nil {
+				if instant.After(parser.Now) {
+					return Period{}, fmt.Errorf("invalid period '%s', it starts in the future", args[0])
+				}
+				return Period{Sd: instant, Ed: parser.Now, Exact: true}, nil
+			}
+
+			since, err := parser.span(sinceValue)
+			if err != nil {
+				return Period{}, err
+			}
+			if since.Sd.After(parser.today()) {
+				return Period{}, fmt.Errorf("invalid period '%s', it starts in the future", args[0])
+			}
+			return Period{Sd: since.Sd, Ed: parser.today()}, nil
+		}
+
+		period, err := parser.span(value)
+		if err != nil {
+			return Period{}, fmt.Errorf("unknown period '%s', expected one of %v, a calendar period like 2026-W42, 2026-10, 2026-Q3 or 2026, 'since <DAY>', 'since 08:00', 'last 4 hours' or a day like today, yesterday, 'last monday', '3 days ago' or a date like %s", args[0], AllowedPeriodFixedTimeFrames(), parser.dateFormatsDescription())
+		}
+		return period, nil
+	case 2:
+		from, err := parser.span(args[0])
+		if err != nil {
+			return Period{}, err
+		}
+		to, err := parser.span(args[1])
+		if err != nil {
+			return Period{}, err
+		}
+
+		if from.Sd.After(to.Sd) {
+			from, to = to, from
+		}
+		return Period{Sd: from.Sd, Ed: to.Ed}, nil
+	default:
+		return Period{}, fmt.Errorf("a period is given by at most two arguments, got %d", len(args))
+	}
+}
+
+// ParseDay returns the start of the day given either as a date or relatively to today
+func (parser PeriodParser) ParseDay(value string) (time.Time, error) {
+	value = normalizedPeriodArgument(value)
+	today := parser.today()
+	switch value {
+	case todayDay:
+		return today, nil
+	case yesterdayDay:
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if match := daysAgoPattern.FindStringSubmatch(value); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		switch match[2][0] {
+		case 'd':
+			return today.AddDate(0, 0, -amount), nil
+		case 'w':
+			return today.AddDate(0, 0, -7*amount), nil
+		case 'm':
+			return today.AddDate(0, -amount, 0), nil
+		default:
+			return today.AddDate(-amount, 0, 0), nil
+		}
+	}
+
+	weekdayName := strings.TrimPrefix(value, lastPrefix)
+	if weekday, ok := utils.ParseWeekday(weekdayName); ok {
+		daysBack := (int(today.Weekday()) - int(weekday) + 7) % 7
+		if daysBack == 0 && weekdayName != value {
+			daysBack = 7
+		}
+		return today.AddDate(0, 0, -daysBack), nil
+	}
+
+	for _, format := range append([]string{utils.DateFormat}, parser.DateFormats...) {
+		date, err := time.ParseInLocation(format, value, parser.Now.Location())
+		if err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid day '%s', expected today, yesterday, 'last monday', '3 days ago' or a date like %s", value, parser.dateFormatsDescription())
+}
+
+// span returns the period covered by a single day or by a calendar period in ISO notation
+func (parser PeriodParser) span(value string) (Period, error) {
+	value = normalizedPeriodArgument(value)
+	period, ok, err := parser.isoPeriod(value)
+	if ok || err != nil {
+		return period, err
+	}
+
+	day, err := parser.ParseDay(value)
+	if err != nil {
+		return Period{}, err
+	}
+	return Period{Sd: day, Ed: day}, nil
+}
+
+// keywordPeriod returns the period of a fixed time frame: either a rolling one ending today, as in "week",
+// or a calendar one, as in "this-week" or "last-month"
+func (parser PeriodParser) keywordPeriod(keyword string) (Period, bool) {
+	if period, ok := periodFromKeyWord(keyword, parser.Now); ok {
+		return period, true
+	}
+
+	today := parser.today()
+	var unit string
+	var offset int
+	switch {
+	case strings.HasPrefix(keyword, thisPrefix):
+		unit = strings.TrimPrefix(keyword, thisPrefix)
+	case strings.HasPrefix(keyword, lastCalendarPrefix):
+		unit = strings.TrimPrefix(keyword, lastCalendarPrefix)
+		offset = -1
+	default:
+		return Period{}, false
+	}
+
+	switch unit {
+	case weekUnit:
+		return parser.week(today.AddDate(0, 0, 7*offset)), true
+	case monthUnit:
+		return monthPeriod(today.Year(), today.Month()+time.Month(offset), 1, today.Location()), true
+	case quarterUnit:
+		firstMonth := (today.Month()-1)/3*3 + 1
+		return monthPeriod(today.Year(), firstMonth+time.Month(3*offset), 3, today.Location()), true
+	case yearUnit:
+		return monthPeriod(today.Year()+offset, time.January, 12, today.Location()), true
+	}
+	return Period{}, false
+}
+
+// isoPeriod returns the calendar period given in ISO notation, as in 2026-W42, 2026-10, 2026-Q3 or 2026.
+// It returns false if the value is not in ISO notation, and an error if it is but the period does not exist.
+func (parser PeriodParser) isoPeriod(value string) (Period, bool, error) {
+	loc := parser.Now.Location()
+	if match := isoYearPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		return monthPeriod(year, time.January, 12, loc), true, nil
+	}
+
+	if match := isoQuarterPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		quarter, _ := strconv.Atoi(match[2])
+		return monthPeriod(year, time.Month(3*quarter-2), 3, loc), true, nil
+	}
+
+	if match := isoMonthPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		if month < 1 || month > 12 {
+			return Period{}, true, fmt.Errorf("invalid month '%s'", value)
+		}
+		return monthPeriod(year, time.Month(month), 1, loc), true, nil
+	}
+
+	if match := isoWeekPattern.FindStringSubmatch(value); match != nil {
+		year, _ := strconv.Atoi(match[1])
+		week, _ := strconv.Atoi(match[2])
+		// The first ISO week of a year is the one with January 4th, and ISO weeks start on Monday
+		january4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+		monday := january4.AddDate(0, 0, -(int(january4.Weekday())+6)%7+7*(week-1))
+		if isoYear, isoWeek := monday.ISOWeek(); isoYear != year || isoWeek != week {
+			return Period{}, true, fmt.Errorf("invalid week '%s', %d has no week %d", value, year, week)
+		}
+		return parser.week(monday), true, nil
+	}
+
+	return Period{}, false, nil
+}
+
+// week returns the week, starting on the configured first day of the week, with the given day
+func (parser PeriodParser) week(day time.Time) Period {
+	sd := day.AddDate(0, 0, -(int(day.Weekday())-int(parser.WeekStart)+7)%7)
+	return Period{Sd: sd, Ed: sd.AddDate(0, 0, 6)}
+}
+
+// monthPeriod returns the period of the given number of months, starting at the given month
+func monthPeriod(year int, month time.Month, months int, loc *time.Location) Period {
+	sd := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return Period{Sd: sd, Ed: sd.AddDate(0, months, -1)}
+}
+
+func (parser PeriodParser) today() time.Time {
+	return startOfDay(parser.Now, parser.Now.Location())
+}
+
+// dateFormatsDescription returns the accepted date formats, as an example date of each
+func (parser PeriodParser) dateFormatsDescription() string {
+	example := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	examples := []string{example.Format(utils.DateFormat)}
+	for _, format := range parser.DateFormats {
+		examples = append(examples, example.Format(format))
+	}
+	return strings.Join(examples, " or ")
+}
+
+func normalizedPeriodArgument(value string) string {
+	return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+}
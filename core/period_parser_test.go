@@ -0,0 +1,174 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+// Sunday 2026-10-18
+var parserNow = time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+func TestPeriodParserDays(t *testing.T) {
+	parser := PeriodParser{Now: parserNow}
+	expected := map[string]string{
+		"today":        "2026-10-18",
+		"Yesterday":    "2026-10-17",
+		"3 days ago":   "2026-10-15",
+		"1 week ago":   "2026-10-11",
+		"2 months ago": "2026-08-18",
+		"last monday":  "2026-10-12",
+		"friday":       "2026-10-16",
+		"sunday":       "2026-10-18",
+		"last sunday":  "2026-10-11",
+		"2026-09-01":   "2026-09-01",
+	}
+
+	for value, day := range expected {
+		period, err := parser.Parse(value)
+		if err != nil || period.StartDateDay() != day || period.EndDateDay() != day {
+			t.Errorf("Wrong period for '%s': %s to %s (%v)", value, period.StartDateDay(), period.EndDateDay(), err)
+		}
+	}
+}
+
+func TestPeriodParserRanges(t *testing.T) {
+	parser := PeriodParser{Now: parserNow}
+
+	period, err := parser.Parse("3 days ago", "today")
+	if err != nil || period.StartDateDay() != "2026-10-15" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("Wrong period between two days: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("today", "last monday")
+	if err != nil || period.StartDateDay() != "2026-10-12" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("Days given in reverse order should be swapped: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("since 2026-09-01")
+	if err != nil || period.StartDateDay() != "2026-09-01" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("Wrong period since a day: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("week")
+	if err != nil || period.StartDateDay() != "2026-10-11" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("Fixed time frames should still be accepted: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse()
+	if err != nil || period.StartDateDay() != "2026-10-18" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("No argument should be today: %+v (%v)", period, err)
+	}
+}
+
+func TestPeriodParserDateFormats(t *testing.T) {
+	parser := PeriodParser{Now: parserNow, DateFormats: []string{"02/01/2006"}}
+
+	period, err := parser.Parse("01/09/2026", "2026-09-30")
+	if err != nil || period.StartDateDay() != "2026-09-01" || period.EndDateDay() != "2026-09-30" {
+		t.Errorf("Wrong period with configured date formats: %+v (%v)", period, err)
+	}
+
+	if _, err := (PeriodParser{Now: parserNow}).Parse("01/09/2026"); err == nil {
+		t.Error("Should not accept dates in formats that are not configured")
+	}
+}
+
+func TestPeriodParserInvalidArguments(t *testing.T) {
+	parser := PeriodParser{Now: parserNow}
+	for _, args := range [][]string{{"fortnight"}, {""}, {"last"}, {"since tomorrow"}, {"since 2026-12-01"}, {"today", "whenever"}, {"2026-13-01"}} {
+		if _, err := parser.Parse(args...); err == nil {
+			t.Errorf("Should not parse %q", args)
+		}
+	}
+}
+
+func TestPeriodParserCalendarKeywords(t *testing.T) {
+	parser := PeriodParser{Now: parserNow, WeekStart: time.Monday}
+	expected := map[string][2]string{
+		"this-week":    {"2026-10-12", "2026-10-18"},
+		"last-week":    {"2026-10-05", "2026-10-11"},
+		"this-month":   {"2026-10-01", "2026-10-31"},
+		"last-month":   {"2026-09-01", "2026-09-30"},
+		"this-quarter": {"2026-10-01", "2026-12-31"},
+		"last-quarter": {"2026-07-01", "2026-09-30"},
+		"this-year":    {"2026-01-01", "2026-12-31"},
+		"last-year":    {"2025-01-01", "2025-12-31"},
+	}
+
+	for keyword, days := range expected {
+		period, err := parser.Parse(keyword)
+		if err != nil || period.StartDateDay() != days[0] || period.EndDateDay() != days[1] {
+			t.Errorf("Wrong period for '%s': %s to %s (%v)", keyword, period.StartDateDay(), period.EndDateDay(), err)
+		}
+	}
+
+	sundayStart := PeriodParser{Now: parserNow, WeekStart: time.Sunday}
+	period, _ := sundayStart.Parse("this-week")
+	if period.StartDateDay() != "2026-10-18" || period.EndDateDay() != "2026-10-24" {
+		t.Errorf("Weeks should start on the configured day: %s to %s", period.StartDateDay(), period.EndDateDay())
+	}
+}
+
+func TestPeriodParserISONotations(t *testing.T) {
+	parser := PeriodParser{Now: parserNow, WeekStart: time.Monday}
+	expected := map[string][2]string{
+		"2026-W42": {"2026-10-12", "2026-10-18"},
+		"2026-w01": {"2025-12-29", "2026-01-04"},
+		"2020-W53": {"2020-12-28", "2021-01-03"},
+		"2026-10":  {"2026-10-01", "2026-10-31"},
+		"2024-02":  {"2024-02-01", "2024-02-29"},
+		"2026-Q3":  {"2026-07-01", "2026-09-30"},
+		"2026":     {"2026-01-01", "2026-12-31"},
+	}
+
+	for notation, days := range expected {
+		period, err := parser.Parse(notation)
+		if err != nil || period.StartDateDay() != days[0] || period.EndDateDay() != days[1] {
+			t.Errorf("Wrong period for '%s': %s to %s (%v)", notation, period.StartDateDay(), period.EndDateDay(), err)
+		}
+	}
+
+	period, err := parser.Parse("2026-W40", "2026-W42")
+	if err != nil || period.StartDateDay() != "2026-09-28" || period.EndDateDay() != "2026-10-18" {
+		t.Errorf("Two calendar periods should cover both: %+v (%v)", period, err)
+	}
+
+	period, _ = PeriodParser{Now: parserNow, WeekStart: time.Sunday}.Parse("2026-W42")
+	if period.StartDateDay() != "2026-10-11" || period.EndDateDay() != "2026-10-17" {
+		t.Errorf("ISO weeks should start on the configured day: %s to %s", period.StartDateDay(), period.EndDateDay())
+	}
+
+	for _, notation := range []string{"2025-W53", "2026-W00", "2026-13", "2026-Q5"} {
+		if _, err := parser.Parse(notation); err == nil {
+			t.Errorf("Should not parse '%s'", notation)
+		}
+	}
+}
+
+func TestPeriodParserExactPeriods(t *testing.T) {
+	parser := PeriodParser{Now: parserNow}
+
+	period, err := parser.Parse("last 4 hours")
+	if err != nil || !period.Exact || !period.Sd.Equal(parserNow.Add(-4*time.Hour)) || !period.Ed.Equal(parserNow) {
+		t.Errorf("Wrong period for the last hours: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("last 30 minutes")
+	if err != nil || !period.Sd.Equal(parserNow.Add(-30*time.Minute)) {
+		t.Errorf("Wrong period for the last minutes: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("since 08:00")
+	if err != nil || !period.Exact || !period.Sd.Equal(time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)) || !period.Ed.Equal(parserNow) {
+		t.Errorf("Wrong period since a time of day: %+v (%v)", period, err)
+	}
+
+	period, err = parser.Parse("since 2026-10-17 22:00")
+	if err != nil || !period.Exact || !period.Sd.Equal(time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong period since a date and time: %+v (%v)", period, err)
+	}
+
+	if _, err := parser.Parse("since 16:00"); err == nil {
+		t.Error("Should not accept a period starting in the future")
+	}
+}
@@ -140,19 +140,17 @@ func TestPeriodFromDateStringsWhenSdIsBeforeEd(t *testing.T) {
 }
 
 func TestPeriodFromKeyWordWithInvalidKeyword(t *testing.T) {
-	period1 := PeriodFromKeyWord("")
-	if period1 != PeriodFromKeyWord("day") {
-		t.Error("Calling PeriodFromKeyWord with empty string didnt produce default period")
+	if _, err := PeriodFromKeyWord(""); err == nil {
+		t.Error("Calling PeriodFromKeyWord with empty string should fail")
 	}
 
-	period2 := PeriodFromKeyWord("invalid")
-	if period2 != PeriodFromKeyWord("day") {
-		t.Error("Calling PeriodFromKeyWord with invalid keyword didnt produce default period")
+	if _, err := PeriodFromKeyWord("invalid"); err == nil {
+		t.Error("Calling PeriodFromKeyWord with invalid keyword should fail")
 	}
 }
 
 func TestPeriodFromKeyWordDay(t *testing.T) {
-	period := PeriodFromKeyWord("day")
+	period, _ := PeriodFromKeyWord("day")
 	sd := period.Sd
 	ed := period.Ed
 	now := time.Now()
@@ -178,7 +176,7 @@ func TestPeriodFromKeyWordDay(t *testing.T) {
 }
 
 func TestPeriodFromKeyWordWeek(t *testing.T) {
-	period := PeriodFromKeyWord("week")
+	period, _ := PeriodFromKeyWord("week")
 	sd := period.Sd
 	ed := period.Ed
 	now := time.Now()
@@ -204,7 +202,7 @@ func TestPeriodFromKeyWordWeek(t *testing.T) {
 }
 
 func TestPeriodFromKeyWordYear(t *testing.T) {
-	period := PeriodFromKeyWord("year")
+	period, _ := PeriodFromKeyWord("year")
 	sd := period.Sd
 	ed := period.Ed
 	now := time.Now()
@@ -228,3 +226,98 @@ func TestPeriodFromKeyWordYear(t *testing.T) {
 		t.Error("Invalid period created for keyword 'year' (wrong ed day)")
 	}
 }
+
+func TestForEachDayIncludesFirstAndLastDays(t *testing.T) {
+	period, _ := PeriodFromDateStrings("2020-10-10", "2020-10-12")
+
+	var days []string
+	period.ForEachDay(func(d time.Time) error {
+		days = append(days, d.Format("2006-01-02"))
+		return nil
+	})
+
+	if len(days) != 3 || days[0] != "2020-10-10" || days[2] != "2020-10-12" {
+		t.Errorf("Should have iterated over 3 days, got %v", days)
+	}
+}
+
+func TestNumberOfDaysAndForEachDayAcrossDSTChange(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	if err != nil {
+		t.Skip("Time zone database not available")
+	}
+
+	// Clocks went back one hour on 2020-10-25 in Lisbon, so that day lasted 25 hours.
+	period := Period{
+		Sd: time.Date(2020, 10, 24, 0, 0, 0, 0, lisbon),
+		Ed: time.Date(2020, 10, 26, 0, 0, 0, 0, lisbon),
+	}
+
+	if period.NumberOfDays() != 2 {
+		t.Errorf("Number of period days should be 2 across a DST change, got %d", period.NumberOfDays())
+	}
+
+	var days []string
+	period.ForEachDay(func(d time.Time) error {
+		days = append(days, d.Format("2006-01-02"))
+		return nil
+	})
+
+	if len(days) != 3 || days[1] != "2020-10-25" || days[2] != "2020-10-26" {
+		t.Errorf("Should have iterated over each calendar day once, got %v", days)
+	}
+}
+
+func TestPeriodIntervalContainsAndIntersect(t *testing.T) {
+	days, _ := PeriodFromDateStrings("2020-10-10", "2020-10-11")
+	bounds := days.Interval()
+	if !bounds.Start.Equal(time.Date(2020, 10, 10, 0, 0, 0, 0, time.UTC)) || !bounds.End.Equal(time.Date(2020, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("A period of days should go from the first midnight to the midnight after its last day: %+v", bounds)
+	}
+
+	if !days.Contains(time.Date(2020, 10, 11, 23, 59, 0, 0, time.UTC)) || days.Contains(bounds.End) {
+		t.Error("A period of days should contain its last day, and not the day after")
+	}
+
+	exact := Period{Sd: time.Date(2020, 10, 10, 8, 0, 0, 0, time.UTC), Ed: time.Date(2020, 10, 10, 12, 0, 0, 0, time.UTC), Exact: true}
+	session := Interval{Start: time.Date(2020, 10, 10, 7, 0, 0, 0, time.UTC), End: time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC)}
+	clipped, ok := exact.Intersect(session)
+	if !ok || !clipped.Start.Equal(exact.Sd) || !clipped.End.Equal(session.End) {
+		t.Errorf("The interval should be cut to the period: %+v", clipped)
+	}
+
+	if exact.Overlaps(Interval{Start: exact.Ed, End: exact.Ed.Add(time.Hour)}) {
+		t.Error("An interval starting when the period ends should not overlap it")
+	}
+
+	endingAtMidnight := Period{Sd: exact.Sd, Ed: time.Date(2020, 10, 11, 0, 0, 0, 0, time.UTC), Exact: true}
+	if exact.EndDateDay() != "2020-10-10" || endingAtMidnight.EndDateDay() != "2020-10-10" {
+		t.Error("The last day of an exact period ending at midnight should be the day before")
+	}
+}
+
+func TestPeriodSplits(t *testing.T) {
+	// 2026-09-29 is a Tuesday
+	days, _ := PeriodFromDateStrings("2026-09-29", "2026-10-13")
+
+	byDay := days.SplitByDay()
+	if len(byDay) != 15 || byDay[0].StartDateDay() != "2026-09-29" || byDay[14].EndDateDay() != "2026-10-13" || byDay[3].Exact {
+		t.Errorf("Wrong split by day: %+v", byDay)
+	}
+
+	byWeek := days.SplitByWeek(time.Monday)
+	if len(byWeek) != 3 || byWeek[0].EndDateDay() != "2026-10-04" || byWeek[1].StartDateDay() != "2026-10-05" || byWeek[2].EndDateDay() != "2026-10-13" {
+		t.Errorf("Wrong split by week: %+v", byWeek)
+	}
+
+	byMonth := days.SplitByMonth()
+	if len(byMonth) != 2 || byMonth[0].EndDateDay() != "2026-09-30" || byMonth[1].StartDateDay() != "2026-10-01" {
+		t.Errorf("Wrong split by month: %+v", byMonth)
+	}
+
+	exact := Period{Sd: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC), Ed: time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC), Exact: true}
+	exactDays := exact.SplitByDay()
+	if len(exactDays) != 2 || !exactDays[0].Exact || !exactDays[0].Sd.Equal(exact.Sd) || exactDays[0].Ed.Hour() != 0 || !exactDays[1].Ed.Equal(exact.Ed) {
+		t.Errorf("An exact period should be split at midnight: %+v", exactDays)
+	}
+}
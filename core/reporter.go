@@ -1,8 +1,14 @@
 package core
 
+import "time"
+
 // Reporter is an object responsible for producing activities reports
 type Reporter interface {
 	Initialize(ActivityRepository, Period) error
 	ProduceReport() error
 	SetDurationFormat(DurationFormat)
+	SetFilter(LogFilter)
+	SetTagTotals(bool)
+	SetDepth(int)
+	SetMaxSession(time.Duration)
 }
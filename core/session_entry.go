@@ -0,0 +1,306 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTimeFormats are the accepted formats of a time of day
+var clockTimeFormats = []string{"15:04", "15:04:05"}
+
+// SessionEntry describes a session entered by hand, for time that was not tracked live.
+// Either two of From, To and Duration are given, or Duration and EndingNow.
+type SessionEntry struct {
+	// Date is the day of From and To, given as to the PeriodParser, as in "yesterday", today if empty
+	Date string
+	// From is the time of day the session started, as in "09:00"
+	From string
+	// To is the time of day the session stopped. A time before From is on the next day.
+	To       string
+	Duration time.Duration
+	// EndingNow makes the session stop at the current instant
+	EndingNow bool
+}
+
+// Interval returns the instants the session started and stopped, relatively to the current instant of the parser
+// and in its time zone. The date is read by the parser as a day of a period.
+func (entry SessionEntry) Interval(parser PeriodParser) (Interval, error) {
+	now := parser.Now
+	day := now
+	if entry.Date != "" {
+		if entry.EndingNow {
+			return Interval{}, errors.New("a session ending now cannot be given a date")
+		}
+
+		date, err := parser.ParseDay(entry.Date)
+		if err != nil {
+			return Interval{}, err
+		}
+		day = date
+	}
+
+	if entry.Duration < 0 {
+		return Interval{}, errors.New("the duration of a session cannot be negative")
+	}
+
+	if entry.EndingNow {
+		if entry.From != "" || entry.To != "" || entry.Duration == 0 {
+			return Interval{}, errors.New("a session ending now needs a duration, and no start or stop time")
+		}
+		return Interval{Start: now.Add(-entry.Duration), End: now}, nil
+	}
+
+	given := 0
+	for _, isGiven := range []bool{entry.From != "", entry.To != "", entry.Duration != 0} {
+		if isGiven {
+			given++
+		}
+	}
+	if given != 2 {
+		return Interval{}, errors.New("a session needs two of a start time, a stop time and a duration, or a duration ending now")
+	}
+
+	var start, end time.Time
+	var err error
+	if entry.From != "" {
+		start, err = ParseClockTime(entry.From, day)
+		if err != nil {
+			return Interval{}, err
+		}
+	}
+
+	if entry.To != "" {
+		end, err = ParseClockTime(entry.To, day)
+		if err != nil {
+			return Interval{}, err
+		}
+	}
+
+	switch {
+	case entry.From == "":
+		start = end.Add(-entry.Duration)
+	case entry.To == "":
+		end = start.Add(entry.Duration)
+	case !end.After(start):
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return Interval{Start: start, End: end}, nil
+}
+
+// dateTimeFormats are the accepted formats of a date with a time of day
+var dateTimeFormats = []string{"2006-01-02 15:04", "2006-01-02 15:04:05"}
+
+// ParseInstant returns the instant given either as a date with a time of day, as in "2026-10-17 09:30",
+// or as a time of day on the day of the given date, in the time zone of the given date.
+func ParseInstant(value string, day time.Time) (time.Time, error) {
+	for _, format := range dateTimeFormats {
+		instant, err := time.ParseInLocation(format, value, day.Location())
+		if err == nil {
+			return instant, nil
+		}
+	}
+
+	instant, err := ParseClockTime(value, day)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected a time of day like 15:04 or a date and time like 2006-01-02 15:04", value)
+	}
+	return instant, nil
+}
+
+// relativeInstantPattern matches an instant given relatively to now, as in "10 minutes ago"
+var relativeInstantPattern = regexp.MustCompile(`^(\d+)\s*(seconds?|secs?|s|minutes?|mins?|m|hours?|h|days?|d)\s+ago$`)
+
+// relativeInstantUnits are the durations of the units of a relative instant, by their first letter
+var relativeInstantUnits = map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour}
+
+// ParseInstantArgument returns the instant given in the command line, relatively to now, in the time zone of now.
+// It is either "now", a duration ago, as in "10 minutes ago" or "1h30m ago", a time of day today, as in "17:45",
+// or a date and time, as in "2026-10-17 17:45" or "2026-10-17T17:45:00+01:00".
+func ParseInstantArgument(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "now" {
+		return now, nil
+	}
+
+	if match := relativeInstantPattern.FindStringSubmatch(value); match != nil {
+		amount, _ := strconv.Atoi(match[1])
+		return now.Add(-time.Duration(amount) * relativeInstantUnits[match[2][0]]), nil
+	}
+
+	if strings.HasSuffix(value, " ago") {
+		duration, err := time.ParseDuration(strings.TrimSpace(strings.TrimSuffix(value, " ago")))
+		if err != nil || duration < 0 {
+			return time.Time{}, fmt.Errorf("invalid time '%s', expected a duration ago like '10 minutes ago' or '1h30m ago'", value)
+		}
+		return now.Add(-duration), nil
+	}
+
+	if instant, err := time.Parse(time.RFC3339, strings.ToUpper(value)); err == nil {
+		return instant.In(now.Location()), nil
+	}
+
+	instant, err := ParseInstant(value, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s', expected 'now', a duration ago like '10 minutes ago', a time of day like 15:04 or a date and time like 2006-01-02 15:04", value)
+	}
+	return instant, nil
+}
+
+// ParseUntilArgument returns the instant a session starting at the given one is planned to end at, given in the
+// command line either as a time of day, as in "12:00", which is on the next day if it is not after the start,
+// or as a date and time, as in "2026-10-17 12:00".
+func ParseUntilArgument(value string, start time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if end, err := ParseClockTime(value, start); err == nil {
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		return end, nil
+	}
+
+	end, err := ParseInstant(value, start)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid end '%s', expected a time of day like 15:04 or a date and time like 2006-01-02 15:04", value)
+	}
+	return end, nil
+}
+
+// ParseClockTime returns the instant at the given time of day, as in "09:30", on the day of the given date.
+func ParseClockTime(value string, day time.Time) (time.Time, error) {
+	for _, format := range clockTimeFormats {
+		clock, err := time.Parse(format, value)
+		if err == nil {
+			return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location()), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s', expected a time of day like 15:04", value)
+}
+
+// CheckStart returns an error if a session cannot start at the instant: it must not start in the future,
+// nor while one of the given logs was running.
+func CheckStart(instant time.Time, logs []ActivityLog, now time.Time) error {
+	if instant.After(now) {
+		return errors.New("a session cannot start in the future")
+	}
+
+	for _, log := range logs {
+		if log.Overlaps(Interval{Start: instant, End: now}, now) {
+			return fmt.Errorf("the session would overlap a session of '%s' %s", log.Activity.Name, log.Describe())
+		}
+	}
+
+	return nil
+}
+
+// CheckPlannedEnd returns an error if a session starting at the instant cannot be planned to end at the given one
+func CheckPlannedEnd(instant time.Time, plannedEnd *time.Time) error {
+	if plannedEnd != nil && !plannedEnd.After(instant) {
+		return fmt.Errorf("the session cannot be planned to end before it starts, at %s", instant.Format(dateTimeFormats[0]))
+	}
+	return nil
+}
+
+// CheckStop returns an error if the running session cannot stop at the instant: it must stop after it started,
+// not in the future, and not before any of its pauses.
+func CheckStop(log ActivityLog, instant time.Time, now time.Time) error {
+	if instant.After(now) {
+		return errors.New("a session cannot stop in the future")
+	}
+
+	if !instant.After(*log.StartedAt) {
+		return fmt.Errorf("the session cannot stop before it started, at %s", log.StartedAt.Format(dateTimeFormats[0]))
+	}
+
+	for _, pause := range log.Pauses {
+		if pause.PausedAt.After(instant) || (pause.ResumedAt != nil && pause.ResumedAt.After(instant)) {
+			return fmt.Errorf("the session was paused at %s, it cannot stop before that", pause.PausedAt.Format(dateTimeFormats[0]))
+		}
+	}
+
+	return nil
+}
+
+// ConflictingLogs returns the logs that cannot overlap a session of the activity, see Activity.CanRunAlongside
+func ConflictingLogs(activity Activity, logs []ActivityLog, concurrentTimers bool) []ActivityLog {
+	var conflicting []ActivityLog
+	for _, log := range logs {
+		if !activity.CanRunAlongside(log.Activity, concurrentTimers) {
+			conflicting = append(conflicting, log)
+		}
+	}
+	return conflicting
+}
+
+// CheckPause returns an error if none of the running logs can be paused
+func CheckPause(running []ActivityLog) error {
+	if len(running) == 0 {
+		return errors.New("you are not tracking any activity, there is nothing to pause")
+	}
+
+	for _, log := range running {
+		if !log.IsPaused() {
+			return nil
+		}
+	}
+
+	if len(running) == 1 {
+		return fmt.Errorf("the activity '%s' is already paused, resume it with the 'resume' command", running[0].Activity.Name)
+	}
+	return errors.New("the activities you are tracking are already paused, resume them with the 'resume' command")
+}
+
+// CheckResume returns an error if none of the running logs is paused
+func CheckResume(running []ActivityLog) error {
+	for _, log := range running {
+		if log.IsPaused() {
+			return nil
+		}
+	}
+	return errors.New("there is no paused activity to resume")
+}
+
+// CheckSession returns an error if the session, as changed, is not valid: a stopped session follows the rules
+// of CheckSessionInterval, and a running session must have started in the past without overlapping other sessions.
+// The session itself, if among the given logs, is ignored.
+func CheckSession(log ActivityLog, logs []ActivityLog, now time.Time) error {
+	var others []ActivityLog
+	for _, other := range logs {
+		if other.Id != log.Id {
+			others = append(others, other)
+		}
+	}
+
+	if log.StoppedAt == nil {
+		if !log.StartedAt.Before(now) {
+			return errors.New("a running session must start in the past")
+		}
+		return CheckSessionInterval(Interval{Start: *log.StartedAt, End: now}, others, now)
+	}
+
+	return CheckSessionInterval(Interval{Start: *log.StartedAt, End: *log.StoppedAt}, others, now)
+}
+
+// CheckSessionInterval returns an error if a session cannot be recorded in the interval: it must end after
+// it starts, not end in the future, and not overlap any of the given logs.
+func CheckSessionInterval(interval Interval, logs []ActivityLog, now time.Time) error {
+	if !interval.End.After(interval.Start) {
+		return errors.New("a session must stop after it starts")
+	}
+
+	if interval.End.After(now) {
+		return errors.New("a session cannot stop in the future")
+	}
+
+	for _, log := range logs {
+		if log.Overlaps(interval, now) {
+			return fmt.Errorf("the session overlaps a session of '%s' %s", log.Activity.Name, log.Describe())
+		}
+	}
+
+	return nil
+}
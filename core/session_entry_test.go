@@ -0,0 +1,217 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionEntryFromAndTo(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	interval, err := SessionEntry{From: "09:00", To: "10:30"}.Interval(PeriodParser{Now: now})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !interval.Start.Equal(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)) || interval.End.Sub(interval.Start) != 90*time.Minute {
+		t.Errorf("Wrong interval: %+v", interval)
+	}
+}
+
+func TestSessionEntryOnAnotherDateCrossingMidnight(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	interval, err := SessionEntry{Date: "2 days ago", From: "23:00", To: "01:00"}.Interval(PeriodParser{Now: now})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !interval.Start.Equal(time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)) || !interval.End.Equal(time.Date(2026, 10, 17, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong interval: %+v", interval)
+	}
+
+	parser := PeriodParser{Now: now, DateFormats: []string{"02/01/2006"}}
+	for _, date := range []string{"2026-10-16", "16/10/2026", "friday"} {
+		interval, err = SessionEntry{Date: date, From: "23:00", To: "01:00"}.Interval(parser)
+		if err != nil || !interval.Start.Equal(time.Date(2026, 10, 16, 23, 0, 0, 0, time.UTC)) {
+			t.Errorf("Wrong interval on %s: %+v (%v)", date, interval, err)
+		}
+	}
+}
+
+func TestSessionEntryWithDuration(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+
+	endingNow, err := SessionEntry{Duration: 90 * time.Minute, EndingNow: true}.Interval(PeriodParser{Now: now})
+	if err != nil || !endingNow.End.Equal(now) || !endingNow.Start.Equal(now.Add(-90*time.Minute)) {
+		t.Errorf("Wrong interval ending now: %+v (%v)", endingNow, err)
+	}
+
+	fromAndDuration, err := SessionEntry{From: "09:00", Duration: time.Hour}.Interval(PeriodParser{Now: now})
+	if err != nil || !fromAndDuration.End.Equal(time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Wrong interval from a start time: %+v (%v)", fromAndDuration, err)
+	}
+}
+
+func TestSessionEntryWithInvalidCombinations(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	entries := []SessionEntry{
+		{From: "09:00"},
+		{From: "09:00", To: "10:00", Duration: time.Hour},
+		{EndingNow: true},
+		{From: "09:00", Duration: time.Hour, EndingNow: true},
+		{From: "9h", To: "10:00"},
+		{Date: "17/10/2026", From: "09:00", To: "10:00"},
+	}
+
+	for _, entry := range entries {
+		if _, err := entry.Interval(PeriodParser{Now: now}); err == nil {
+			t.Errorf("Should have refused the entry %+v", entry)
+		}
+	}
+}
+
+func TestCheckSessionIntervalRefusesOverlapsAndFutureSessions(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	coding := Activity{Id: 1, Name: "coding"}
+	logs := []ActivityLog{newTestLog(coding, time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), time.Hour)}
+
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, 10, 18, hour, min, 0, 0, time.UTC)
+	}
+
+	if err := CheckSessionInterval(Interval{Start: at(10, 0), End: at(11, 0)}, logs, now); err != nil {
+		t.Errorf("Should accept a session right after another one: %v", err)
+	}
+
+	if CheckSessionInterval(Interval{Start: at(9, 30), End: at(11, 0)}, logs, now) == nil {
+		t.Error("Should refuse an overlapping session")
+	}
+
+	if CheckSessionInterval(Interval{Start: at(14, 0), End: at(16, 0)}, logs, now) == nil {
+		t.Error("Should refuse a session stopping in the future")
+	}
+
+	if CheckSessionInterval(Interval{Start: at(12, 0), End: at(11, 0)}, logs, now) == nil {
+		t.Error("Should refuse a session stopping before it starts")
+	}
+}
+
+func TestCheckSessionIgnoresTheSessionItself(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	coding := Activity{Id: 1, Name: "coding"}
+	log := newTestLog(coding, time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), time.Hour)
+	logs := []ActivityLog{log}
+
+	newStart := time.Date(2026, 10, 18, 8, 30, 0, 0, time.UTC)
+	edited := SessionUpdate{StartedAt: &newStart}.Apply(log)
+
+	if err := CheckSession(edited, logs, now); err != nil {
+		t.Errorf("Should accept moving the start of the session: %v", err)
+	}
+
+	newStop := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+	if CheckSession(SessionUpdate{StoppedAt: &newStop}.Apply(log), logs, now) == nil {
+		t.Error("Should refuse a session stopping before it starts")
+	}
+}
+
+func TestParseInstant(t *testing.T) {
+	day := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	instant, err := ParseInstant("2026-10-17 09:30", day)
+	if err != nil || !instant.Equal(time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Wrong instant from a date and time: %v (%v)", instant, err)
+	}
+
+	instant, err = ParseInstant("09:30", day)
+	if err != nil || !instant.Equal(time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Wrong instant from a time of day: %v (%v)", instant, err)
+	}
+
+	if _, err := ParseInstant("yesterday", day); err == nil {
+		t.Error("Should refuse an invalid instant")
+	}
+}
+
+func TestParseInstantArgument(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	expected := map[string]time.Time{
+		"now":                       now,
+		"10 minutes ago":            now.Add(-10 * time.Minute),
+		"1 hour ago":                now.Add(-time.Hour),
+		"2 days ago":                now.AddDate(0, 0, -2),
+		"1h30m ago":                 now.Add(-90 * time.Minute),
+		"17:45":                     time.Date(2026, 10, 18, 17, 45, 0, 0, time.UTC),
+		"2026-10-17 09:30":          time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC),
+		"2026-10-17T09:30:00+01:00": time.Date(2026, 10, 17, 8, 30, 0, 0, time.UTC),
+	}
+
+	for value, instant := range expected {
+		parsed, err := ParseInstantArgument(value, now)
+		if err != nil || !parsed.Equal(instant) {
+			t.Errorf("Wrong instant for '%s': %v (%v)", value, parsed, err)
+		}
+	}
+
+	for _, value := range []string{"", "soon", "ten minutes ago", "-5m ago", "25:00"} {
+		if _, err := ParseInstantArgument(value, now); err == nil {
+			t.Errorf("Should not parse '%s'", value)
+		}
+	}
+}
+
+func TestCheckStartAndStop(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	start := now.Add(-3 * time.Hour)
+	stop := now.Add(-2 * time.Hour)
+	stopped := ActivityLog{Id: 1, StartedAt: &start, StoppedAt: &stop}
+
+	if err := CheckStart(now.Add(-90*time.Minute), []ActivityLog{stopped}, now); err != nil {
+		t.Errorf("Should start after the other session: %v", err)
+	}
+
+	if CheckStart(now.Add(-150*time.Minute), []ActivityLog{stopped}, now) == nil {
+		t.Error("Should not start during another session")
+	}
+
+	if CheckStart(now.Add(time.Minute), nil, now) == nil {
+		t.Error("Should not start in the future")
+	}
+
+	pausedAt := now.Add(-30 * time.Minute)
+	running := ActivityLog{Id: 2, StartedAt: &stop, Pauses: []Pause{{PausedAt: &pausedAt}}}
+	if err := CheckStop(running, now.Add(-10*time.Minute), now); err != nil {
+		t.Errorf("Should stop during the pause: %v", err)
+	}
+
+	for _, instant := range []time.Time{now.Add(time.Minute), stop.Add(-time.Minute), now.Add(-time.Hour)} {
+		if CheckStop(running, instant, now) == nil {
+			t.Errorf("Should not stop at %v", instant)
+		}
+	}
+}
+
+func TestParseUntilArgument(t *testing.T) {
+	start := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"12:00":            time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		"08:00":            time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		"2026-10-20 12:00": time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC),
+	}
+
+	for value, expected := range cases {
+		end, err := ParseUntilArgument(value, start)
+		if err != nil || !end.Equal(expected) {
+			t.Errorf("Should parse '%s' as %v, got %v (%v)", value, expected, end, err)
+		}
+	}
+
+	if _, err := ParseUntilArgument("noon", start); err == nil {
+		t.Error("Should not parse 'noon'")
+	}
+
+	if CheckPlannedEnd(start, &start) == nil {
+		t.Error("Should not plan a session to end when it starts")
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// StartOptions holds the optional data of a tracking session, given when it starts
+type StartOptions struct {
+	// Note is a free text describing what is done in the session
+	Note string
+	// Tags of the session, on top of the ones of its activity
+	Tags []string
+	// At is the instant the session started, now if nil
+	At *time.Time
+	// Until is the instant the session is planned to end, after which it is considered stopped. No end is planned if nil.
+	Until *time.Time
+}
+
+// StartedAt returns the instant the session started
+func (opts StartOptions) StartedAt(now time.Time) time.Time {
+	return instantOrNow(opts.At, now)
+}
+
+// StopOptions holds the optional data of a tracking session, given when it stops
+type StopOptions struct {
+	// Note is added to the note the session may already have
+	Note string
+	// At is the instant the session stopped, now if nil
+	At *time.Time
+}
+
+// StoppedAt returns the instant the session stopped
+func (opts StopOptions) StoppedAt(now time.Time) time.Time {
+	return instantOrNow(opts.At, now)
+}
+
+func instantOrNow(at *time.Time, now time.Time) time.Time {
+	if at == nil {
+		return now
+	}
+	return *at
+}
+
+// SessionUpdate holds the changes to a recorded session. Nil fields are left unchanged.
+type SessionUpdate struct {
+	StartedAt *time.Time
+	StoppedAt *time.Time
+	Activity  *Activity
+}
+
+// Apply returns the session with the changes
+func (update SessionUpdate) Apply(log ActivityLog) ActivityLog {
+	if update.StartedAt != nil {
+		startedAt := *update.StartedAt
+		log.StartedAt = &startedAt
+	}
+	if update.StoppedAt != nil {
+		stoppedAt := *update.StoppedAt
+		log.StoppedAt = &stoppedAt
+	}
+	if update.Activity != nil {
+		log.Activity = *update.Activity
+	}
+	return log
+}
+
+// noteSeparator separates the notes added to a session at different moments
+const noteSeparator = "; "
+
+// AppendNote adds a note to an existing one
+func AppendNote(existing, note string) string {
+	note = strings.TrimSpace(note)
+	if existing == "" {
+		return note
+	}
+
+	if note == "" {
+		return existing
+	}
+
+	return existing + noteSeparator + note
+}
@@ -0,0 +1,54 @@
+package core
+
+import "time"
+
+// SessionStatus describes the session being tracked, as shown in shell prompts and status bars
+type SessionStatus struct {
+	// Tracking is false when no session is running, in which case the other fields are empty
+	Tracking bool
+	Activity Activity
+	Start    time.Time
+	Paused   bool
+	// Elapsed is the time spent in the session, without its pauses
+	Elapsed time.Duration
+	// Today is the time spent on the activity during the period, the session included
+	Today time.Duration
+	// Remaining is the time left until the planned end of the session, zero if it has none
+	Remaining time.Duration
+}
+
+// NewSessionStatus returns the status of the session started last among the given running logs, along with the time
+// spent on its activity during the period, from the given logs of that activity. Logs running past their planned end
+// are considered stopped at that end.
+func NewSessionStatus(logs []ActivityLog, period Period, now time.Time) SessionStatus {
+	logs = SettleOverdueLogs(logs, now)
+	var current *ActivityLog
+	for i := range logs {
+		if logs[i].IsRunning() && (current == nil || !logs[i].StartedAt.Before(*current.StartedAt)) {
+			current = &logs[i]
+		}
+	}
+
+	if current == nil {
+		return SessionStatus{}
+	}
+
+	status := SessionStatus{
+		Tracking: true,
+		Activity: current.Activity,
+		Start:    *current.StartedAt,
+		Paused:   current.IsPaused(),
+		Elapsed:  current.Elapsed(now),
+	}
+	if remaining, ok := current.Remaining(now); ok {
+		status.Remaining = remaining
+	}
+
+	for _, log := range logs {
+		if log.Activity.Id == current.Activity.Id {
+			status.Today += log.ElapsedIn(period, now)
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,32 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionStatusOfRunningLog(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding"}
+	midnight := time.Date(2020, 10, 10, 0, 0, 0, 0, time.UTC)
+	startedAt := midnight.Add(9 * time.Hour)
+	plannedEnd := midnight.Add(12 * time.Hour)
+	running := ActivityLog{Activity: coding, StartedAt: &startedAt, PlannedEnd: &plannedEnd, Pauses: []Pause{newTestPause(startedAt.Add(15*time.Minute), 30*time.Minute)}}
+	logs := []ActivityLog{newTestLog(coding, midnight.Add(-time.Hour), 2*time.Hour), running}
+	today := Period{Sd: midnight, Ed: midnight}
+
+	status := NewSessionStatus(logs, today, midnight.Add(10*time.Hour+30*time.Minute))
+	if !status.Tracking || status.Activity.Name != "coding" || !status.Start.Equal(startedAt) {
+		t.Fatalf("Should be tracking coding, got %+v", status)
+	}
+	if status.Elapsed != time.Hour || status.Today != 2*time.Hour || status.Remaining != 90*time.Minute {
+		t.Errorf("Wrong durations, got elapsed %v, today %v and remaining %v", status.Elapsed, status.Today, status.Remaining)
+	}
+
+	status = NewSessionStatus(logs, today, midnight.Add(13*time.Hour))
+	if status.Tracking {
+		t.Errorf("Should not be tracking past the planned end, got %+v", status)
+	}
+	if logs[1].StoppedAt != nil {
+		t.Errorf("Should not change the given logs, got %+v", logs[1])
+	}
+}
@@ -0,0 +1,156 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SessionChanges is a set of changes to the recorded sessions, applied all at once
+type SessionChanges struct {
+	// Inserts are new sessions, without id
+	Inserts []ActivityLog
+	// Updates replace the start, stop, activity and note of the sessions with the same ids
+	Updates []ActivityLog
+	// Deletes are the ids of the deleted sessions
+	Deletes []int
+}
+
+// IsEmpty returns true if there are no changes
+func (changes SessionChanges) IsEmpty() bool {
+	return len(changes.Inserts) == 0 && len(changes.Updates) == 0 && len(changes.Deletes) == 0
+}
+
+const sessionTextTimeFormat = "2006-01-02 15:04"
+const sessionTextSeparator = "|"
+const sessionTextNewId = "new"
+const sessionTextRunning = "-"
+
+// FormatSessionsText returns the sessions as text to be edited by hand, one line per session, with times in the given time zone.
+func FormatSessionsText(sessions []ActivityLog, loc *time.Location) string {
+	var text strings.Builder
+	text.WriteString("# One session per line: id | start | stop | activity | note\n")
+	text.WriteString("# Delete a line to delete its session, add a line with 'new' as id to add a session.\n")
+	text.WriteString("# The stop of the running session is '-'. Lines starting with '#' are ignored.\n")
+	for _, session := range sessions {
+		text.WriteString(formatSessionLine(strconv.Itoa(session.Id), session.In(loc)))
+		text.WriteString("\n")
+	}
+	return text.String()
+}
+
+func formatSessionLine(id string, session ActivityLog) string {
+	fields := []string{id, formatSessionTime(session.StartedAt), formatSessionTime(session.StoppedAt), session.Activity.Name}
+	if session.Note != "" {
+		fields = append(fields, session.Note)
+	}
+	return strings.Join(fields, " "+sessionTextSeparator+" ")
+}
+
+func formatSessionTime(instant *time.Time) string {
+	if instant == nil {
+		return sessionTextRunning
+	}
+	return instant.Format(sessionTextTimeFormat)
+}
+
+// ParseSessionsText compares the text edited by hand with the original sessions it was made from, and returns the changes.
+// Times are read in the given time zone, and activities are found, by name or alias, with the given function.
+// Times left untouched keep their original precision.
+func ParseSessionsText(text string, originals []ActivityLog, loc *time.Location, find func(string) (*Activity, error)) (SessionChanges, error) {
+	byId := make(map[int]ActivityLog)
+	for _, original := range originals {
+		byId[original.Id] = original.In(loc)
+	}
+
+	changes := SessionChanges{}
+	seen := make(map[int]bool)
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, sessionTextSeparator, 5)
+		if len(fields) < 4 {
+			return SessionChanges{}, fmt.Errorf("line %d: expected 'id | start | stop | activity | note'", i+1)
+		}
+		for j := range fields {
+			fields[j] = strings.TrimSpace(fields[j])
+		}
+
+		session := ActivityLog{}
+		original, isUpdate := ActivityLog{}, false
+		if fields[0] != sessionTextNewId {
+			id, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return SessionChanges{}, fmt.Errorf("line %d: invalid id '%s', expected a session id or '%s'", i+1, fields[0], sessionTextNewId)
+			}
+			original, isUpdate = byId[id]
+			if !isUpdate {
+				return SessionChanges{}, fmt.Errorf("line %d: unknown session %d", i+1, id)
+			}
+			if seen[id] {
+				return SessionChanges{}, fmt.Errorf("line %d: session %d is listed more than once", i+1, id)
+			}
+			seen[id] = true
+			session = original
+		}
+
+		var err error
+		session.StartedAt, err = parseSessionTime(fields[1], original.StartedAt, loc)
+		if err != nil || session.StartedAt == nil {
+			return SessionChanges{}, fmt.Errorf("line %d: invalid start '%s', expected a date and time like %s", i+1, fields[1], sessionTextTimeFormat)
+		}
+
+		session.StoppedAt, err = parseSessionTime(fields[2], original.StoppedAt, loc)
+		if err != nil {
+			return SessionChanges{}, fmt.Errorf("line %d: invalid stop '%s', expected a date and time like %s, or '%s'", i+1, fields[2], sessionTextTimeFormat, sessionTextRunning)
+		}
+
+		if fields[3] != original.Activity.Name {
+			activity, err := find(fields[3])
+			if err != nil {
+				return SessionChanges{}, fmt.Errorf("line %d: %s '%s'", i+1, err.Error(), fields[3])
+			}
+			session.Activity = *activity
+		}
+
+		session.Note = ""
+		if len(fields) == 5 {
+			session.Note = fields[4]
+		}
+
+		if !isUpdate {
+			changes.Inserts = append(changes.Inserts, session)
+		} else if formatSessionLine("", session) != formatSessionLine("", original) || session.Activity.Id != original.Activity.Id {
+			changes.Updates = append(changes.Updates, session)
+		}
+	}
+
+	for _, original := range originals {
+		if !seen[original.Id] {
+			changes.Deletes = append(changes.Deletes, original.Id)
+		}
+	}
+
+	return changes, nil
+}
+
+// parseSessionTime parses a time of the text, returning the original time if the text was not changed
+func parseSessionTime(value string, original *time.Time, loc *time.Location) (*time.Time, error) {
+	if value == formatSessionTime(original) {
+		return original, nil
+	}
+
+	if value == sessionTextRunning {
+		return nil, nil
+	}
+
+	instant, err := time.ParseInLocation(sessionTextTimeFormat, value, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &instant, nil
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSessionsText() ([]ActivityLog, func(string) (*Activity, error)) {
+	coding := Activity{Id: 1, Name: "coding", Alias: "c"}
+	reading := Activity{Id: 2, Name: "reading"}
+	first := newTestLog(coding, time.Date(2026, 10, 18, 9, 0, 30, 0, time.UTC), time.Hour)
+	first.Id = 1
+	first.Note = "parser"
+	second := newTestLog(reading, time.Date(2026, 10, 18, 11, 0, 0, 0, time.UTC), time.Hour)
+	second.Id = 2
+
+	find := func(nameOrAlias string) (*Activity, error) {
+		for _, activity := range []Activity{coding, reading} {
+			if activity.Name == nameOrAlias || activity.Alias == nameOrAlias {
+				found := activity
+				return &found, nil
+			}
+		}
+		return nil, errors.New("activity not found")
+	}
+
+	return []ActivityLog{first, second}, find
+}
+
+func TestParseSessionsTextWithoutChanges(t *testing.T) {
+	sessions, find := newTestSessionsText()
+	text := FormatSessionsText(sessions, time.UTC)
+
+	if !strings.Contains(text, "1 | 2026-10-18 09:00 | 2026-10-18 10:00 | coding | parser\n") {
+		t.Errorf("Wrong text for the sessions:\n%s", text)
+	}
+
+	changes, err := ParseSessionsText(text, sessions, time.UTC, find)
+	if err != nil || !changes.IsEmpty() {
+		t.Errorf("Unchanged text should have no changes, got %+v (%v)", changes, err)
+	}
+}
+
+func TestParseSessionsTextWithChanges(t *testing.T) {
+	sessions, find := newTestSessionsText()
+	text := `
+1 | 2026-10-18 09:00 | 2026-10-18 10:15 | c | parser and tests
+new | 2026-10-18 12:30 | 2026-10-18 13:00 | reading
+`
+
+	changes, err := ParseSessionsText(text, sessions, time.UTC, find)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes.Updates) != 1 || changes.Updates[0].Note != "parser and tests" || !changes.Updates[0].StoppedAt.Equal(time.Date(2026, 10, 18, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("Wrong updates: %+v", changes.Updates)
+	}
+
+	if !changes.Updates[0].StartedAt.Equal(time.Date(2026, 10, 18, 9, 0, 30, 0, time.UTC)) {
+		t.Errorf("Untouched start should keep its seconds, got %v", changes.Updates[0].StartedAt)
+	}
+
+	if len(changes.Inserts) != 1 || changes.Inserts[0].Activity.Id != 2 || changes.Inserts[0].Note != "" {
+		t.Errorf("Wrong inserts: %+v", changes.Inserts)
+	}
+
+	if len(changes.Deletes) != 1 || changes.Deletes[0] != 2 {
+		t.Errorf("Wrong deletes: %v", changes.Deletes)
+	}
+}
+
+func TestParseSessionsTextWithErrors(t *testing.T) {
+	sessions, find := newTestSessionsText()
+	texts := []string{
+		"1 | 2026-10-18 09:00 | coding",
+		"3 | 2026-10-18 09:00 | 2026-10-18 10:00 | coding",
+		"x | 2026-10-18 09:00 | 2026-10-18 10:00 | coding",
+		"1 | 09:00 | 2026-10-18 10:00 | coding",
+		"1 | 2026-10-18 09:00 | 2026-10-18 10:00 | writing",
+		"1 | 2026-10-18 09:00 | 2026-10-18 10:00 | coding\n1 | 2026-10-18 09:00 | 2026-10-18 10:00 | coding",
+	}
+
+	for _, text := range texts {
+		if _, err := ParseSessionsText(text, sessions, time.UTC, find); err == nil {
+			t.Errorf("Should have refused the text:\n%s", text)
+		}
+	}
+}
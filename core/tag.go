@@ -0,0 +1,74 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TagPrefix marks a command line argument as a tag, as in `tt start coding +billable`
+const TagPrefix = "+"
+
+var tagRegex = regexp.MustCompile(`^[0-9a-zA-Z_-]+$`)
+
+// ParseTag validates a tag, with or without its prefix, and returns it in its canonical form: lower case and without prefix.
+func ParseTag(tag string) (string, error) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), TagPrefix))
+	if !tagRegex.MatchString(name) {
+		return "", fmt.Errorf("tag '%s' is not valid. It must only contain alpha numeric characters, '-' or '_'", tag)
+	}
+	return name, nil
+}
+
+// ParseTags validates a list of tags, returning them in their canonical form, sorted and without duplicates.
+func ParseTags(tags []string) ([]string, error) {
+	parsed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, err := ParseTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, name)
+	}
+	return MergeTags(parsed), nil
+}
+
+// IsTagArgument returns true if the command line argument is a tag, that is, if it starts with the tag prefix
+func IsTagArgument(arg string) bool {
+	return strings.HasPrefix(arg, TagPrefix)
+}
+
+// MergeTags returns the union of the lists of tags, sorted and without duplicates
+func MergeTags(lists ...[]string) []string {
+	seen := make(map[string]bool)
+	merged := []string{}
+	for _, tags := range lists {
+		for _, tag := range tags {
+			if !seen[tag] {
+				seen[tag] = true
+				merged = append(merged, tag)
+			}
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+// FormatTags returns the tags as they are typed in the command line, separated by spaces
+func FormatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = TagPrefix + tag
+	}
+	return strings.Join(formatted, " ")
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTagRemovesPrefixAndLowerCases(t *testing.T) {
+	tag, err := ParseTag("+ClientA")
+
+	if err != nil || tag != "clienta" {
+		t.Errorf("Should have parsed the tag as 'clienta', got '%s' (%v)", tag, err)
+	}
+}
+
+func TestParseTagWithInvalidCharacters(t *testing.T) {
+	for _, tag := range []string{"", "+", "client a", "+client!"} {
+		if _, err := ParseTag(tag); err == nil {
+			t.Errorf("Should have failed to parse tag '%s'", tag)
+		}
+	}
+}
+
+func TestParseTagsSortsAndRemovesDuplicates(t *testing.T) {
+	tags, err := ParseTags([]string{"+billable", "clientA", "+Billable"})
+
+	if err != nil || !reflect.DeepEqual(tags, []string{"billable", "clienta"}) {
+		t.Errorf("Wrong parsed tags: %v (%v)", tags, err)
+	}
+}
+
+func TestLogFilterUsesSessionAndActivityTags(t *testing.T) {
+	coding := Activity{Id: 1, Name: "coding", Tags: []string{"billable"}}
+	log := newTestLog(coding, time.Date(2020, 10, 10, 9, 0, 0, 0, time.UTC), time.Hour)
+	log.Tags = []string{"clienta"}
+
+	tests := []struct {
+		filter   LogFilter
+		expected bool
+	}{
+		{LogFilter{}, true},
+		{LogFilter{Tags: []string{"billable"}}, true},
+		{LogFilter{Tags: []string{"billable", "clienta"}}, true},
+		{LogFilter{Tags: []string{"billable", "clientb"}}, false},
+		{LogFilter{ExcludedTags: []string{"clienta"}}, false},
+		{LogFilter{Tags: []string{"billable"}, ExcludedTags: []string{"personal"}}, true},
+	}
+
+	for _, tc := range tests {
+		if tc.filter.Matches(log) != tc.expected {
+			t.Errorf("Filter %+v should match %v", tc.filter, tc.expected)
+		}
+	}
+}
@@ -4,7 +4,7 @@ go 1.16
 
 require (
 	github.com/golang-migrate/migrate/v4 v4.14.1
-	github.com/mattn/go-sqlite3 v1.14.8 // indirect
+	github.com/mattn/go-sqlite3 v1.14.8
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/pflag v1.0.5 // indirect
 )
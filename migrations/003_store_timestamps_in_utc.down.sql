@@ -0,0 +1,6 @@
+UPDATE activity_logs
+SET started_at = DATETIME(started_at, started_at_offset || ' seconds'),
+    stopped_at = DATETIME(stopped_at, stopped_at_offset || ' seconds');
+
+ALTER TABLE activity_logs DROP COLUMN stopped_at_offset;
+ALTER TABLE activity_logs DROP COLUMN started_at_offset;
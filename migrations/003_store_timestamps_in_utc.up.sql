@@ -0,0 +1,11 @@
+ALTER TABLE activity_logs ADD COLUMN started_at_offset integer NOT NULL DEFAULT 0;
+ALTER TABLE activity_logs ADD COLUMN stopped_at_offset integer;
+
+UPDATE activity_logs
+SET started_at_offset = CAST(ROUND((JulianDay(started_at) - JulianDay(started_at, 'utc')) * 24 * 60 * 60) AS integer),
+    started_at = DATETIME(started_at, 'utc');
+
+UPDATE activity_logs
+SET stopped_at_offset = CAST(ROUND((JulianDay(stopped_at) - JulianDay(stopped_at, 'utc')) * 24 * 60 * 60) AS integer),
+    stopped_at = DATETIME(stopped_at, 'utc')
+WHERE stopped_at IS NOT NULL;
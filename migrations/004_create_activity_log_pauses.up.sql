@@ -0,0 +1,12 @@
+CREATE TABLE activity_log_pauses (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  activity_log_id integer NOT NULL,
+  paused_at timestamp NOT NULL,
+  paused_at_offset integer NOT NULL DEFAULT 0,
+  resumed_at timestamp,
+  resumed_at_offset integer,
+  FOREIGN KEY(activity_log_id) REFERENCES activity_logs(id)
+);
+
+CREATE INDEX activity_log_index
+ON activity_log_pauses(activity_log_id);
@@ -0,0 +1 @@
+ALTER TABLE activity_logs ADD COLUMN note text NOT NULL DEFAULT '';
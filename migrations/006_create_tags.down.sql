@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS activity_log_tags;
+DROP TABLE IF EXISTS activity_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,20 @@
+CREATE TABLE tags (
+  id integer PRIMARY KEY AUTOINCREMENT,
+  name text NOT NULL UNIQUE
+);
+
+CREATE TABLE activity_tags (
+  activity_id integer NOT NULL,
+  tag_id integer NOT NULL,
+  PRIMARY KEY(activity_id, tag_id),
+  FOREIGN KEY(activity_id) REFERENCES activities(id),
+  FOREIGN KEY(tag_id) REFERENCES tags(id)
+);
+
+CREATE TABLE activity_log_tags (
+  activity_log_id integer NOT NULL,
+  tag_id integer NOT NULL,
+  PRIMARY KEY(activity_log_id, tag_id),
+  FOREIGN KEY(activity_log_id) REFERENCES activity_logs(id),
+  FOREIGN KEY(tag_id) REFERENCES tags(id)
+);
@@ -0,0 +1 @@
+ALTER TABLE activities DROP COLUMN archived;
@@ -0,0 +1 @@
+ALTER TABLE activities ADD COLUMN archived integer NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE activity_logs DROP COLUMN planned_end_offset;
+ALTER TABLE activity_logs DROP COLUMN planned_end;
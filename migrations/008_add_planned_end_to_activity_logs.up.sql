@@ -0,0 +1,2 @@
+ALTER TABLE activity_logs ADD COLUMN planned_end timestamp;
+ALTER TABLE activity_logs ADD COLUMN planned_end_offset integer;
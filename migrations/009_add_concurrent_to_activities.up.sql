@@ -0,0 +1 @@
+ALTER TABLE activities ADD COLUMN concurrent integer NOT NULL DEFAULT 0;
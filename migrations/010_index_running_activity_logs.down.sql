@@ -0,0 +1,2 @@
+DROP INDEX activity_stopped_at_index;
+DROP INDEX stopped_at_index;
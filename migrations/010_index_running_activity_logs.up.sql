@@ -0,0 +1,5 @@
+CREATE INDEX stopped_at_index
+ON activity_logs(stopped_at);
+
+CREATE INDEX activity_stopped_at_index
+ON activity_logs(activity_id, stopped_at);
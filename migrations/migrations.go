@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations so they ship inside the binary.
+package migrations
+
+import "embed"
+
+// FS holds every up and down migration file of the database schema.
+//
+//go:embed *.sql
+var FS embed.FS
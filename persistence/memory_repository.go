@@ -0,0 +1,946 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+// MemoryRepository is an activity repository that keeps all its data in memory. Nothing is persisted between runs,
+// which makes it useful for tests and for tooling built on top of the ActivityRepository interface.
+type MemoryRepository struct {
+	mu          sync.Mutex
+	activities  []core.Activity
+	logs        []core.ActivityLog
+	nextId      int
+	nextLogId   int
+	nextPauseId int
+	location    *time.Location
+	// concurrentTimers lets sessions of any activities run at the same time
+	concurrentTimers bool
+	Clock            utils.Clock
+}
+
+// NewMemoryRepository creates a new, empty, in-memory repository
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		Clock:       utils.NewLiveClock(),
+		nextId:      1,
+		nextLogId:   1,
+		nextPauseId: 1,
+		location:    time.Local,
+	}
+}
+
+// Initialize sets the time zone used to group sessions by day and whether sessions of any activities can run at the
+// same time, the in-memory repository needs no other setup
+func (repo *MemoryRepository) Initialize(config config.Config) error {
+	if config.Location != nil {
+		repo.location = config.Location
+	}
+	repo.concurrentTimers = config.ConcurrentTimers
+	return nil
+}
+
+// Shutdown is a no-op, the in-memory repository holds no resources
+func (repo *MemoryRepository) Shutdown() error {
+	return nil
+}
+
+// Add adds a new activity to the repository
+func (repo *MemoryRepository) Add(activity core.Activity) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, existing := range repo.activities {
+		if existing.Name == activity.Name {
+			return fmt.Errorf("an activity with name '%s' already exists", activity.Name)
+		}
+	}
+
+	err := repo.checkParentExists(activity.Name)
+	if err != nil {
+		return err
+	}
+
+	activity.Id = repo.nextId
+	activity.Tags = core.MergeTags(activity.Tags)
+	repo.nextId++
+	repo.activities = append(repo.activities, activity)
+	return nil
+}
+
+// Delete deletes an activity from the repository, along with its logs or moving them to another activity
+func (repo *MemoryRepository) Delete(activityNameOrAlias string, opts core.DeleteOptions) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	deleted, err := repo.find(activityNameOrAlias)
+	if err != nil {
+		return err
+	}
+
+	for _, activity := range repo.activities {
+		if activity.IsDescendantOf(deleted.Name) {
+			return fmt.Errorf("activity '%s' has sub-activities, please delete them first", deleted.Name)
+		}
+	}
+
+	if core.LogOfActivity(repo.runningLogs(), deleted.Id) != nil {
+		return fmt.Errorf("you are tracking the activity '%s', please stop it before deleting it", deleted.Name)
+	}
+
+	hasLogs := false
+	for _, log := range repo.logs {
+		hasLogs = hasLogs || log.Activity.Id == deleted.Id
+	}
+
+	err = opts.Check(*deleted, hasLogs)
+	if err != nil {
+		return err
+	}
+
+	if opts.DeleteLogs {
+		repo.wipe(func(log core.ActivityLog) bool {
+			return log.Activity.Id == deleted.Id
+		})
+	}
+
+	if opts.MoveLogsTo != nil {
+		if _, ok := repo.byId(opts.MoveLogsTo.Id); !ok {
+			return errors.New("activity to move the logs to not found")
+		}
+
+		for i := range repo.logs {
+			if repo.logs[i].Activity.Id == deleted.Id {
+				repo.logs[i].Activity = core.Activity{Id: opts.MoveLogsTo.Id}
+			}
+		}
+	}
+
+	remaining := repo.activities[:0]
+	for _, activity := range repo.activities {
+		if activity.Id != deleted.Id {
+			remaining = append(remaining, activity)
+		}
+	}
+
+	repo.activities = remaining
+	return nil
+}
+
+// List returns a list with all the activities in the repository
+func (repo *MemoryRepository) List() ([]core.Activity, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	activities := make([]core.Activity, len(repo.activities))
+	copy(activities, repo.activities)
+	return activities, nil
+}
+
+// Find returns an activity
+func (repo *MemoryRepository) Find(activityNameOrAlias string) (*core.Activity, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.find(activityNameOrAlias)
+}
+
+// Update updates an activity
+func (repo *MemoryRepository) Update(activityNameOrAlias string, updateOp core.UpdateActivity) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	activity, err := repo.find(activityNameOrAlias)
+	if err != nil {
+		return err
+	}
+
+	oldName := activity.Name
+	updateOp.Visit(activity)
+
+	if activity.Name != oldName {
+		renamed := core.Activity{Name: oldName}
+		err = renamed.ValidateRename(activity.Name)
+		if err != nil {
+			return err
+		}
+
+		if existing, _ := repo.find(activity.Name); existing != nil {
+			return fmt.Errorf("an activity with name '%s' already exists", activity.Name)
+		}
+
+		err = repo.checkParentExists(activity.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range repo.activities {
+		if repo.activities[i].Id == activity.Id {
+			repo.activities[i] = *activity
+		} else {
+			repo.activities[i].Name = repo.activities[i].RenameAncestor(oldName, activity.Name)
+		}
+	}
+
+	return nil
+}
+
+// Archive archives an activity and its sub-activities
+func (repo *MemoryRepository) Archive(activityNameOrAlias string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	activity, err := repo.find(activityNameOrAlias)
+	if err != nil {
+		return err
+	}
+
+	for _, running := range repo.runningLogs() {
+		if running.Activity.Id == activity.Id || running.Activity.IsDescendantOf(activity.Name) {
+			return fmt.Errorf("you are tracking the activity '%s', please stop it before archiving it", running.Activity.Name)
+		}
+	}
+
+	repo.setArchived(*activity, true)
+	return nil
+}
+
+// Unarchive restores an archived activity and its sub-activities
+func (repo *MemoryRepository) Unarchive(activityNameOrAlias string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	activity, err := repo.find(activityNameOrAlias)
+	if err != nil {
+		return err
+	}
+
+	repo.setArchived(*activity, false)
+	return nil
+}
+
+// Start starts tracking the time for an activity
+func (repo *MemoryRepository) Start(activity core.Activity, opts core.StartOptions) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	for _, running := range repo.runningLogs() {
+		if !running.Activity.CanRunAlongside(activity, repo.concurrentTimers) {
+			return fmt.Errorf("you are already tracking the activity '%s', please stop that one before starting a new one", running.Activity.Name)
+		}
+	}
+
+	err := repo.checkNotArchived(activity)
+	if err != nil {
+		return err
+	}
+
+	now := repo.Clock.Now()
+	startedAt := opts.StartedAt(now)
+	err = core.CheckStart(startedAt, core.ConflictingLogs(activity, repo.resolvedLogs(), repo.concurrentTimers), now)
+	if err == nil {
+		err = core.CheckPlannedEnd(startedAt, opts.Until)
+	}
+	if err != nil {
+		return err
+	}
+
+	repo.insertLog(activity, startedAt, nil, opts)
+	return nil
+}
+
+// LogSession records a session of an activity that was not tracked live
+func (repo *MemoryRepository) LogSession(activity core.Activity, interval core.Interval, opts core.StartOptions) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	err := repo.checkNotArchived(activity)
+	if err != nil {
+		return err
+	}
+
+	err = core.CheckSessionInterval(interval, core.ConflictingLogs(activity, repo.resolvedLogs(), repo.concurrentTimers), repo.Clock.Now())
+	if err != nil {
+		return err
+	}
+
+	repo.insertLog(activity, interval.Start, &interval.End, opts)
+	return nil
+}
+
+// Stop stops tracking the time for an activity
+func (repo *MemoryRepository) Stop(activity core.Activity, opts core.StopOptions) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	runningLogs := repo.runningLogs()
+	if len(runningLogs) == 0 {
+		return errors.New("you are not tracking any activity, please start tracking one with the 'start' command")
+	}
+
+	running := core.LogOfActivity(runningLogs, activity.Id)
+	if running == nil {
+		return fmt.Errorf("you are not tracking the activity '%s'", activity.Name)
+	}
+
+	stoppedAt := opts.StoppedAt(repo.Clock.Now())
+	err := core.CheckStop(*running, stoppedAt, repo.Clock.Now())
+	if err != nil {
+		return err
+	}
+
+	repo.stopLog(running.Id, stoppedAt, opts)
+	return nil
+}
+
+// Switch stops the sessions being tracked of activities that are not concurrent, if any, and starts tracking the time
+// for another activity at the same instant
+func (repo *MemoryRepository) Switch(activity core.Activity, opts core.StartOptions) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	runningLogs := repo.runningLogs()
+	if running := core.LogOfActivity(runningLogs, activity.Id); running != nil {
+		return fmt.Errorf("you are already tracking the activity '%s'", running.Activity.Name)
+	}
+
+	err := repo.checkNotArchived(activity)
+	if err != nil {
+		return err
+	}
+
+	now := repo.Clock.Now()
+	switchedAt := opts.StartedAt(now)
+	switchedFrom := make(map[int]bool)
+	for _, running := range runningLogs {
+		if running.Activity.Concurrent {
+			continue
+		}
+
+		err = core.CheckStop(running, switchedAt, now)
+		if err != nil {
+			return err
+		}
+		switchedFrom[running.Id] = true
+	}
+
+	var stopped []core.ActivityLog
+	for _, log := range repo.resolvedLogs() {
+		if switchedFrom[log.Id] {
+			log.StoppedAt = &switchedAt
+		}
+		stopped = append(stopped, log)
+	}
+
+	err = core.CheckStart(switchedAt, core.ConflictingLogs(activity, stopped, repo.concurrentTimers), now)
+	if err == nil {
+		err = core.CheckPlannedEnd(switchedAt, opts.Until)
+	}
+	if err != nil {
+		return err
+	}
+
+	for logId := range switchedFrom {
+		repo.stopLog(logId, switchedAt, core.StopOptions{})
+	}
+	repo.insertLog(activity, switchedAt, nil, opts)
+	return nil
+}
+
+// stopLog stops the running log at the given instant, resuming it first if it is paused
+func (repo *MemoryRepository) stopLog(logId int, now time.Time, opts core.StopOptions) {
+	log := &repo.logs[repo.logIndex(logId)]
+	if pause := log.OpenPause(); pause != nil {
+		pause.ResumedAt = &now
+	}
+	log.StoppedAt = &now
+	log.Note = core.AppendNote(log.Note, opts.Note)
+}
+
+// SessionsForPeriod returns the sessions, running or not, taking place in the days of the period, ordered by their start.
+// If an activity is given, only its sessions are returned.
+func (repo *MemoryRepository) SessionsForPeriod(period core.Period, activity *core.Activity) ([]core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	bounds := period.Anchored(repo.location).Interval()
+	var sessions []core.ActivityLog
+	for _, log := range repo.settledLogs() {
+		if activity != nil && log.Activity.Id != activity.Id {
+			continue
+		}
+
+		if log.Overlaps(bounds, repo.Clock.Now()) {
+			sessions = append(sessions, log)
+		}
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(*sessions[j].StartedAt)
+	})
+	return sessions, nil
+}
+
+// FindSession returns a session by its id
+func (repo *MemoryRepository) FindSession(logId int) (*core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, log := range repo.resolvedLogs() {
+		if log.Id == logId {
+			found := log
+			return &found, nil
+		}
+	}
+
+	return nil, fmt.Errorf("session %d not found", logId)
+}
+
+// UpdateSession changes the start, the stop or the activity of a session
+func (repo *MemoryRepository) UpdateSession(logId int, update core.SessionUpdate) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	logs := repo.resolvedLogs()
+	for i := range repo.logs {
+		if repo.logs[i].Id != logId {
+			continue
+		}
+
+		if update.Activity != nil {
+			err := repo.checkNotArchived(*update.Activity)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated := update.Apply(repo.logs[i])
+		activity, _ := repo.byId(updated.Activity.Id)
+		err := core.CheckSession(updated, core.ConflictingLogs(activity, logs, repo.concurrentTimers), repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		updated.Activity = core.Activity{Id: updated.Activity.Id}
+		updated.Date = updated.StartedAt.In(repo.location).Format(utils.DateFormat)
+		repo.logs[i] = updated
+		return nil
+	}
+
+	return fmt.Errorf("session %d not found", logId)
+}
+
+// DeleteSession deletes a session
+func (repo *MemoryRepository) DeleteSession(logId int) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	count := len(repo.logs)
+	repo.wipe(func(log core.ActivityLog) bool {
+		return log.Id == logId
+	})
+
+	if len(repo.logs) == count {
+		return fmt.Errorf("session %d not found", logId)
+	}
+	return nil
+}
+
+// ApplySessionChanges inserts, updates and deletes sessions all at once. No change is applied if any of them is not valid.
+func (repo *MemoryRepository) ApplySessionChanges(changes core.SessionChanges) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	previousLogs := append([]core.ActivityLog(nil), repo.logs...)
+	previousNextLogId := repo.nextLogId
+	err := repo.applySessionChanges(changes)
+	if err != nil {
+		repo.logs = previousLogs
+		repo.nextLogId = previousNextLogId
+	}
+	return err
+}
+
+func (repo *MemoryRepository) applySessionChanges(changes core.SessionChanges) error {
+	for _, logId := range changes.Deletes {
+		count := len(repo.logs)
+		repo.wipe(func(log core.ActivityLog) bool {
+			return log.Id == logId
+		})
+		if len(repo.logs) == count {
+			return fmt.Errorf("session %d not found", logId)
+		}
+	}
+
+	var changed []int
+	for _, update := range changes.Updates {
+		i := repo.logIndex(update.Id)
+		if i < 0 {
+			return fmt.Errorf("session %d not found", update.Id)
+		}
+
+		if update.Activity.Id != repo.logs[i].Activity.Id {
+			err := repo.checkNotArchived(update.Activity)
+			if err != nil {
+				return err
+			}
+		}
+
+		log := &repo.logs[i]
+		log.StartedAt = update.StartedAt
+		log.StoppedAt = update.StoppedAt
+		log.Activity = core.Activity{Id: update.Activity.Id}
+		log.Note = update.Note
+		log.Date = log.StartedAt.In(repo.location).Format(utils.DateFormat)
+		changed = append(changed, log.Id)
+	}
+
+	for _, insert := range changes.Inserts {
+		err := repo.checkNotArchived(insert.Activity)
+		if err != nil {
+			return err
+		}
+
+		changed = append(changed, repo.nextLogId)
+		repo.insertLog(insert.Activity, *insert.StartedAt, insert.StoppedAt, core.StartOptions{Note: insert.Note, Tags: insert.Tags})
+	}
+
+	logs := repo.resolvedLogs()
+	for _, logId := range changed {
+		log := logs[repo.logIndex(logId)]
+		err := core.CheckSession(log, core.ConflictingLogs(log.Activity, logs, repo.concurrentTimers), repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CurrentlyTrackedActivity returns the activity beeing currently tracked, the one started last if there are several, if any
+func (repo *MemoryRepository) CurrentlyTrackedActivity() (*core.Activity, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	running := repo.runningLogs()
+	if len(running) == 0 {
+		return nil, nil
+	}
+
+	return &running[len(running)-1].Activity, nil
+}
+
+// CurrentSession returns the log of the activity beeing currently tracked, the one started last if there are several, if any
+func (repo *MemoryRepository) CurrentSession() (*core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	running := repo.runningLogs()
+	if len(running) == 0 {
+		return nil, nil
+	}
+
+	return &running[len(running)-1], nil
+}
+
+// CurrentSessions returns the logs of all the activities beeing currently tracked, ordered by their start
+func (repo *MemoryRepository) CurrentSessions() ([]core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.runningLogs(), nil
+}
+
+// CurrentActivityLogs returns the running logs, along with the logs of their activities stopped since the instant,
+// without changing them, so that logs running past their planned end are returned as running
+func (repo *MemoryRepository) CurrentActivityLogs(since time.Time) ([]core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	logs := repo.resolvedLogs()
+	running := make(map[int]bool)
+	for _, log := range logs {
+		if log.IsRunning() {
+			running[log.Activity.Id] = true
+		}
+	}
+
+	var current []core.ActivityLog
+	for _, log := range logs {
+		if running[log.Activity.Id] && (log.IsRunning() || !log.StoppedAt.Before(since)) {
+			current = append(current, log)
+		}
+	}
+
+	sort.SliceStable(current, func(i, j int) bool {
+		return current[i].StartedAt.Before(*current[j].StartedAt)
+	})
+	return current, nil
+}
+
+// LastSession returns the log of the activity tracked most recently, running or not, if any
+func (repo *MemoryRepository) LastSession() (*core.ActivityLog, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var last *core.ActivityLog
+	for _, log := range repo.settledLogs() {
+		if last == nil || !log.StartedAt.Before(*last.StartedAt) {
+			found := log
+			last = &found
+		}
+	}
+
+	return last, nil
+}
+
+// SetNote replaces the note of an activity log
+func (repo *MemoryRepository) SetNote(logId int, note string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for i := range repo.logs {
+		if repo.logs[i].Id == logId {
+			repo.logs[i].Note = core.AppendNote("", note)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("activity log %d not found", logId)
+}
+
+// TagActivity adds tags to an activity
+func (repo *MemoryRepository) TagActivity(activity core.Activity, tags []string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.updateActivityTags(activity, func(existing []string) []string {
+		return core.MergeTags(existing, tags)
+	})
+}
+
+// UntagActivity removes tags from an activity
+func (repo *MemoryRepository) UntagActivity(activity core.Activity, tags []string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return repo.updateActivityTags(activity, func(existing []string) []string {
+		return withoutTags(existing, tags)
+	})
+}
+
+// Tags returns all the tags in use, by activities or sessions, sorted
+func (repo *MemoryRepository) Tags() ([]string, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	var tags []string
+	for _, activity := range repo.activities {
+		tags = core.MergeTags(tags, activity.Tags)
+	}
+	for _, log := range repo.logs {
+		tags = core.MergeTags(tags, log.Tags)
+	}
+	return tags, nil
+}
+
+// RenameTag renames a tag in every activity and session that has it. Renaming to an existing tag merges both.
+func (repo *MemoryRepository) RenameTag(oldName string, newName string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	rename := func(tags []string) ([]string, bool) {
+		if !containsTag(tags, oldName) {
+			return tags, false
+		}
+		return core.MergeTags(withoutTags(tags, []string{oldName}), []string{newName}), true
+	}
+
+	found := false
+	for i := range repo.activities {
+		var renamed bool
+		repo.activities[i].Tags, renamed = rename(repo.activities[i].Tags)
+		found = found || renamed
+	}
+	for i := range repo.logs {
+		var renamed bool
+		repo.logs[i].Tags, renamed = rename(repo.logs[i].Tags)
+		found = found || renamed
+	}
+
+	if !found {
+		return fmt.Errorf("tag '%s' not found", oldName)
+	}
+	return nil
+}
+
+// Pause starts a break in the activities beeing currently tracked
+func (repo *MemoryRepository) Pause() error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	running := repo.runningLogs()
+	err := core.CheckPause(running)
+	if err != nil {
+		return err
+	}
+
+	now := repo.Clock.Now()
+	for _, paused := range running {
+		if paused.IsPaused() {
+			continue
+		}
+
+		log := &repo.logs[repo.logIndex(paused.Id)]
+		log.Pauses = append(log.Pauses, core.Pause{Id: repo.nextPauseId, PausedAt: &now})
+		repo.nextPauseId++
+	}
+	return nil
+}
+
+// Resume ends the break in the activities beeing currently tracked
+func (repo *MemoryRepository) Resume() error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.stopOverdueLogs()
+
+	running := repo.runningLogs()
+	err := core.CheckResume(running)
+	if err != nil {
+		return err
+	}
+
+	now := repo.Clock.Now()
+	for _, resumed := range running {
+		if resumed.IsPaused() {
+			repo.logs[repo.logIndex(resumed.Id)].OpenPause().ResumedAt = &now
+		}
+	}
+	return nil
+}
+
+// LogsForPeriod returns a list of activity logs for a given period.
+// Days are calendar days in the configured time zone, and sessions crossing midnight have their duration
+// apportioned to each day they span.
+func (repo *MemoryRepository) LogsForPeriod(period core.Period, filter core.LogFilter) (map[string][]core.ActivityDurationDayAggregation, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	return core.AggregateLogsByDay(repo.settledLogs(), period, repo.location, filter), nil
+}
+
+// WipeLogsPeriodAndActivity deletes the logs of a given activity started in a given period
+func (repo *MemoryRepository) WipeLogsPeriodAndActivity(period core.Period, activity *core.Activity) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.wipe(func(log core.ActivityLog) bool {
+		return log.Activity.Id == activity.Id && period.Anchored(repo.location).Contains(*log.StartedAt)
+	})
+	return nil
+}
+
+// WipeLogsPeriod deletes the logs started in a given period
+func (repo *MemoryRepository) WipeLogsPeriod(period core.Period) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.wipe(func(log core.ActivityLog) bool {
+		return period.Anchored(repo.location).Contains(*log.StartedAt)
+	})
+	return nil
+}
+
+func (repo *MemoryRepository) find(activityNameOrAlias string) (*core.Activity, error) {
+	for _, activity := range repo.activities {
+		if matchesNameOrAlias(activity, activityNameOrAlias) {
+			found := activity
+			return &found, nil
+		}
+	}
+
+	return nil, errors.New("activity not found")
+}
+
+// logIndex returns the index of the log with the given id, or -1 if there is none.
+func (repo *MemoryRepository) logIndex(logId int) int {
+	for i, log := range repo.logs {
+		if log.Id == logId {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolvedLogs returns a copy of the logs of existing activities, with their activity resolved.
+func (repo *MemoryRepository) resolvedLogs() []core.ActivityLog {
+	var logs []core.ActivityLog
+	for _, log := range repo.logs {
+		activity, ok := repo.byId(log.Activity.Id)
+		if !ok {
+			continue
+		}
+
+		log.Activity = activity
+		log.Pauses = append([]core.Pause(nil), log.Pauses...)
+		logs = append(logs, log)
+	}
+	return logs
+}
+
+func (repo *MemoryRepository) insertLog(activity core.Activity, startedAt time.Time, stoppedAt *time.Time, opts core.StartOptions) {
+	var plannedEnd *time.Time
+	if opts.Until != nil {
+		until := *opts.Until
+		plannedEnd = &until
+	}
+
+	repo.logs = append(repo.logs, core.ActivityLog{
+		Id:         repo.nextLogId,
+		Date:       startedAt.In(repo.location).Format(utils.DateFormat),
+		StartedAt:  &startedAt,
+		StoppedAt:  stoppedAt,
+		Activity:   core.Activity{Id: activity.Id},
+		Note:       core.AppendNote("", opts.Note),
+		PlannedEnd: plannedEnd,
+		Tags:       core.MergeTags(opts.Tags),
+	})
+	repo.nextLogId++
+}
+
+func (repo *MemoryRepository) checkNotArchived(activity core.Activity) error {
+	stored, ok := repo.byId(activity.Id)
+	if !ok {
+		return errors.New("activity not found")
+	}
+
+	if stored.Archived {
+		return fmt.Errorf("activity '%s' is archived, please unarchive it before tracking it", stored.Name)
+	}
+
+	return nil
+}
+
+func (repo *MemoryRepository) setArchived(activity core.Activity, archived bool) {
+	for i := range repo.activities {
+		if repo.activities[i].Id == activity.Id || repo.activities[i].IsDescendantOf(activity.Name) {
+			repo.activities[i].Archived = archived
+		}
+	}
+}
+
+// checkParentExists returns an error if the activity with the given name is a sub-activity of an unknown activity
+func (repo *MemoryRepository) checkParentExists(name string) error {
+	parentName := core.ParentActivityName(name)
+	if parentName == "" {
+		return nil
+	}
+
+	for _, activity := range repo.activities {
+		if activity.Name == parentName {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("parent activity '%s' does not exist, please add it first", parentName)
+}
+
+func (repo *MemoryRepository) updateActivityTags(activity core.Activity, update func([]string) []string) error {
+	for i := range repo.activities {
+		if repo.activities[i].Id == activity.Id {
+			repo.activities[i].Tags = update(repo.activities[i].Tags)
+			return nil
+		}
+	}
+
+	return errors.New("activity not found")
+}
+
+func (repo *MemoryRepository) byId(id int) (core.Activity, bool) {
+	for _, activity := range repo.activities {
+		if activity.Id == id {
+			return activity, true
+		}
+	}
+
+	return core.Activity{}, false
+}
+
+// settledLogs returns the resolved logs with the ones running past their planned end stopped at that end,
+// without changing the stored logs
+func (repo *MemoryRepository) settledLogs() []core.ActivityLog {
+	return core.SettleOverdueLogs(repo.resolvedLogs(), repo.Clock.Now())
+}
+
+// runningLogs returns copies of the logs that were started and not stopped yet, with their activity resolved,
+// ordered by their start. The logs running past their planned end are not running anymore.
+func (repo *MemoryRepository) runningLogs() []core.ActivityLog {
+	var running []core.ActivityLog
+	for _, log := range repo.settledLogs() {
+		if log.IsRunning() {
+			running = append(running, log)
+		}
+	}
+
+	sort.SliceStable(running, func(i, j int) bool {
+		return running[i].StartedAt.Before(*running[j].StartedAt)
+	})
+	return running
+}
+
+// stopOverdueLogs stops the running logs at their planned end, if that end has passed
+func (repo *MemoryRepository) stopOverdueLogs() {
+	for _, log := range repo.logs {
+		if log.IsOverdue(repo.Clock.Now()) {
+			repo.stopLog(log.Id, *log.PlannedEnd, core.StopOptions{})
+		}
+	}
+}
+
+func (repo *MemoryRepository) wipe(shouldWipe func(core.ActivityLog) bool) {
+	remaining := repo.logs[:0]
+	for _, log := range repo.logs {
+		if !shouldWipe(log) {
+			remaining = append(remaining, log)
+		}
+	}
+	repo.logs = remaining
+}
+
+func matchesNameOrAlias(activity core.Activity, activityNameOrAlias string) bool {
+	return activity.Name == activityNameOrAlias || (activity.HasAlias() && activity.Alias == activityNameOrAlias)
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutTags(tags []string, removed []string) []string {
+	remaining := []string{}
+	for _, tag := range tags {
+		if !containsTag(removed, tag) {
+			remaining = append(remaining, tag)
+		}
+	}
+	return remaining
+}
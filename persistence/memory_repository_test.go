@@ -0,0 +1,22 @@
+package persistence
+
+import (
+	"testing"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/persistence/repositorytest"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+func TestMemoryRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T, clock utils.Clock, conf config.Config) core.ActivityRepository {
+		repo := NewMemoryRepository()
+		repo.Clock = clock
+		err := repo.Initialize(conf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	})
+}
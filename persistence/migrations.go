@@ -0,0 +1,218 @@
+package persistence
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/httpfs"
+	"github.com/luispcosta/go-tt/migrations"
+)
+
+// SchemaVersionTable is the table that keeps track of the schema version applied to the database.
+const SchemaVersionTable = "schema_version"
+
+// legacyTables maps the tables created by the manually applied migrations to the schema version they belong to.
+// It is used to adopt databases created before migrations were applied automatically.
+var legacyTables = []struct {
+	table   string
+	version int
+}{
+	{table: "activity_logs", version: 2},
+	{table: "activities", version: 1},
+}
+
+// SchemaVersion returns the schema version currently applied to the database.
+// A version of 0 means no migration was applied yet.
+func (repo *SqliteRepository) SchemaVersion() (version uint, dirty bool, err error) {
+	m, err := repo.newMigrate()
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Migrate applies all the pending migrations to the database.
+func (repo *SqliteRepository) Migrate() error {
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	current, dirty, err := repo.fastSchemaVersion()
+	if err == nil && !dirty && current == latest {
+		return nil
+	}
+
+	m, err := repo.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = adoptLegacySchema(m, repo.db)
+	if err != nil {
+		return err
+	}
+
+	err = ensureSchemaNotNewer(m, latest)
+	if err != nil {
+		return err
+	}
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("could not migrate the database: %w", err)
+	}
+
+	return nil
+}
+
+// MigrateDown reverts the last N applied migrations.
+func (repo *SqliteRepository) MigrateDown(steps int) error {
+	if steps <= 0 {
+		return errors.New("the number of migrations to revert must be greater than zero")
+	}
+
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		return err
+	}
+
+	m, err := repo.newMigrate()
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = ensureSchemaNotNewer(m, latest)
+	if err != nil {
+		return err
+	}
+
+	err = m.Steps(-steps)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("could not revert the database migrations: %w", err)
+	}
+
+	return nil
+}
+
+// LatestSchemaVersion returns the most recent schema version embedded in the binary.
+func LatestSchemaVersion() (uint, error) {
+	entries, err := fs.ReadDir(migrations.FS, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		migration, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			continue
+		}
+		if migration.Version > latest {
+			latest = migration.Version
+		}
+	}
+
+	return latest, nil
+}
+
+// fastSchemaVersion reads the schema version straight from the version table, without going through
+// the migration machinery, so that up to date databases are not slowed down by the migration check.
+func (repo *SqliteRepository) fastSchemaVersion() (uint, bool, error) {
+	var version uint
+	var dirty bool
+	row := repo.db.QueryRow(fmt.Sprintf("SELECT version, dirty FROM %s LIMIT 1", SchemaVersionTable))
+	err := row.Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
+
+// newMigrate builds a migration runner over the embedded migrations. It uses its own connection to the database,
+// since closing the runner also closes the connection it was given.
+func (repo *SqliteRepository) newMigrate() (*migrate.Migrate, error) {
+	src, err := httpfs.New(http.FS(migrations.FS), "/")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", repo.dbFile)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := sqlite3.WithInstance(db, &sqlite3.Config{MigrationsTable: SchemaVersionTable})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return migrate.NewWithInstance("httpfs", src, "sqlite3", driver)
+}
+
+// adoptLegacySchema marks databases whose tables were created by hand with the matching schema version,
+// so that the migrations already applied are not run (and their tables dropped) again.
+func adoptLegacySchema(m *migrate.Migrate, db *sql.DB) error {
+	_, _, err := m.Version()
+	if !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+
+	for _, legacy := range legacyTables {
+		exists, err := tableExists(db, legacy.table)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return m.Force(legacy.version)
+		}
+	}
+
+	return nil
+}
+
+// ensureSchemaNotNewer refuses to work with databases migrated by a more recent version of the application.
+func ensureSchemaNotNewer(m *migrate.Migrate, latest uint) error {
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if version > latest {
+		return fmt.Errorf("the database schema (version %d) is newer than the one supported by this binary (version %d), please upgrade tt", version, latest)
+	}
+
+	if dirty {
+		return fmt.Errorf("the database schema (version %d) is dirty: a previous migration failed and must be fixed manually", version)
+	}
+
+	return nil
+}
+
+func tableExists(db *sql.DB, table string) (bool, error) {
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,1520 @@
+// Package repositorytest provides a conformance test suite that every core.ActivityRepository implementation must pass.
+package repositorytest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+// Factory creates a new, empty repository that reads the current time from the given clock, initialized with
+// the given configuration. Factories of persistent repositories are expected to set the user data location.
+type Factory func(t *testing.T, clock utils.Clock, conf config.Config) core.ActivityRepository
+
+// Run runs the whole conformance suite against the repositories built by the factory.
+// Each test gets its own repository.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		test func(*testing.T, *fixture)
+	}{
+		{"AddAndFind", testAddAndFind},
+		{"FindUnknownActivity", testFindUnknownActivity},
+		{"List", testList},
+		{"Update", testUpdate},
+		{"Delete", testDelete},
+		{"DeleteUnknownActivity", testDeleteUnknownActivity},
+		{"DeleteActivityWithLogs", testDeleteActivityWithLogs},
+		{"DeleteActivityMovingLogs", testDeleteActivityMovingLogs},
+		{"ArchiveAndUnarchive", testArchiveAndUnarchive},
+		{"ArchiveTrackedActivity", testArchiveTrackedActivity},
+		{"AddSubActivity", testAddSubActivity},
+		{"DeleteActivityWithSubActivities", testDeleteActivityWithSubActivities},
+		{"RenameActivityWithSubActivities", testRenameActivityWithSubActivities},
+		{"StartAndStop", testStartAndStop},
+		{"StartWhileTrackingAnotherActivity", testStartWhileTrackingAnotherActivity},
+		{"StopWhenNotTracking", testStopWhenNotTracking},
+		{"StopAnotherActivity", testStopAnotherActivity},
+		{"StartAndStopAtGivenInstants", testStartAndStopAtGivenInstants},
+		{"StartAndStopAtInvalidInstants", testStartAndStopAtInvalidInstants},
+		{"Switch", testSwitch},
+		{"SwitchWhenNotAllowed", testSwitchWhenNotAllowed},
+		{"SessionStoppedAtPlannedEnd", testSessionStoppedAtPlannedEnd},
+		{"ConcurrentActivity", testConcurrentActivity},
+		{"ConcurrentTimersForAllActivities", testConcurrentTimersForAllActivities},
+		{"SplitOverlapOfFilteredLogs", testSplitOverlapOfFilteredLogs},
+		{"StartWithInvalidPlannedEnd", testStartWithInvalidPlannedEnd},
+		{"CurrentActivityLogs", testCurrentActivityLogs},
+		{"LogsForPeriodAggregatesPerDay", testLogsForPeriodAggregatesPerDay},
+		{"LogsForPeriodIgnoresRunningSessions", testLogsForPeriodIgnoresRunningSessions},
+		{"SessionCrossingMidnight", testSessionCrossingMidnight},
+		{"LogsForPeriodSplitsSessionsAcrossDays", testLogsForPeriodSplitsSessionsAcrossDays},
+		{"LogsForPeriodUsesConfiguredTimeZone", testLogsForPeriodUsesConfiguredTimeZone},
+		{"LogSession", testLogSession},
+		{"LogSessionOverlappingAnotherOne", testLogSessionOverlappingAnotherOne},
+		{"SessionsForPeriod", testSessionsForPeriod},
+		{"UpdateSession", testUpdateSession},
+		{"UpdateSessionWithInvalidChanges", testUpdateSessionWithInvalidChanges},
+		{"DeleteSession", testDeleteSession},
+		{"ApplySessionChanges", testApplySessionChanges},
+		{"ApplyInvalidSessionChanges", testApplyInvalidSessionChanges},
+		{"PauseAndResume", testPauseAndResume},
+		{"PauseAndResumeWhenNotAllowed", testPauseAndResumeWhenNotAllowed},
+		{"StopWhilePaused", testStopWhilePaused},
+		{"LogsForPeriodSubtractsPauses", testLogsForPeriodSubtractsPauses},
+		{"SessionNotes", testSessionNotes},
+		{"SetNoteOfLastSession", testSetNoteOfLastSession},
+		{"TagAndUntagActivity", testTagAndUntagActivity},
+		{"SessionTags", testSessionTags},
+		{"LogsForPeriodFilteredByTags", testLogsForPeriodFilteredByTags},
+		{"RenameTag", testRenameTag},
+		{"LogsForExactPeriod", testLogsForExactPeriod},
+		{"WipeLogsPeriod", testWipeLogsPeriod},
+		{"WipeLogsExactPeriod", testWipeLogsExactPeriod},
+		{"WipeLogsPeriodAndActivity", testWipeLogsPeriodAndActivity},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t, factory, time.Local)
+			defer f.repo.Shutdown()
+			tc.test(t, f)
+		})
+	}
+}
+
+type fixture struct {
+	repo    core.ActivityRepository
+	clock   *utils.MockedClock
+	factory Factory
+}
+
+// newFixture creates a repository configured with the given time zone, with the clock set to 2020-10-10 09:00 in it.
+func newFixture(t *testing.T, factory Factory, location *time.Location) *fixture {
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, location))
+	repo := factory(t, clock, config.Config{Location: location})
+	return &fixture{repo: repo, clock: clock, factory: factory}
+}
+
+// add adds an activity and returns it as stored in the repository.
+func (f *fixture) add(t *testing.T, name, alias string) core.Activity {
+	t.Helper()
+	err := f.repo.Add(core.Activity{Name: name, Alias: alias, Description: name + " description"})
+	if err != nil {
+		t.Fatalf("Should have added activity %s: %v", name, err)
+	}
+
+	activity, err := f.repo.Find(name)
+	if err != nil {
+		t.Fatalf("Should have found activity %s: %v", name, err)
+	}
+	return *activity
+}
+
+// track records a completed session of the activity, starting at the given instant.
+func (f *fixture) track(t *testing.T, activity core.Activity, startedAt time.Time, duration time.Duration) {
+	t.Helper()
+	f.clock.SetNow(startedAt)
+	err := f.repo.Start(activity, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity %s: %v", activity.Name, err)
+	}
+
+	f.clock.SetNow(startedAt.Add(duration))
+	err = f.repo.Stop(activity, core.StopOptions{})
+	if err != nil {
+		t.Fatalf("Should have stopped activity %s: %v", activity.Name, err)
+	}
+}
+
+func (f *fixture) at(hour, min int) time.Time {
+	now := f.clock.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, now.Location())
+}
+
+func testAddAndFind(t *testing.T, f *fixture) {
+	added := f.add(t, "coding", "c")
+
+	if added.Id == 0 {
+		t.Error("Found activity should have an id")
+	}
+
+	if added.Description != "coding description" {
+		t.Errorf("Found activity has the wrong description: %s", added.Description)
+	}
+
+	byAlias, err := f.repo.Find("c")
+	if err != nil {
+		t.Fatalf("Should have found activity by alias: %v", err)
+	}
+
+	if byAlias.Id != added.Id || byAlias.Name != "coding" {
+		t.Errorf("Finding by alias returned the wrong activity: %+v", byAlias)
+	}
+}
+
+func testFindUnknownActivity(t *testing.T, f *fixture) {
+	f.add(t, "coding", "c")
+
+	activity, err := f.repo.Find("reading")
+	if err == nil {
+		t.Errorf("Should have failed to find an unknown activity, found %+v", activity)
+	}
+}
+
+func testList(t *testing.T, f *fixture) {
+	f.add(t, "coding", "c")
+	f.add(t, "reading", "")
+
+	activities, err := f.repo.List()
+	if err != nil {
+		t.Fatalf("Should have listed activities: %v", err)
+	}
+
+	if len(activities) != 2 {
+		t.Fatalf("Should have listed 2 activities, got %d", len(activities))
+	}
+
+	names := map[string]bool{}
+	for _, activity := range activities {
+		names[activity.Name] = true
+	}
+
+	if !names["coding"] || !names["reading"] {
+		t.Errorf("Listed the wrong activities: %+v", activities)
+	}
+}
+
+func testUpdate(t *testing.T, f *fixture) {
+	f.add(t, "coding", "c")
+
+	err := f.repo.Update("c", core.UpdateActivityNameAndDescription{Name: "programming", Desc: "Writing code"})
+	if err != nil {
+		t.Fatalf("Should have updated activity: %v", err)
+	}
+
+	activity, err := f.repo.Find("programming")
+	if err != nil {
+		t.Fatalf("Should have found the renamed activity: %v", err)
+	}
+
+	if activity.Description != "Writing code" || activity.Alias != "c" {
+		t.Errorf("Activity was not correctly updated: %+v", activity)
+	}
+
+	_, err = f.repo.Find("coding")
+	if err == nil {
+		t.Error("Should not find the activity by its old name")
+	}
+}
+
+func testDelete(t *testing.T, f *fixture) {
+	f.add(t, "coding", "c")
+	f.add(t, "reading", "")
+
+	err := f.repo.Delete("c", core.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Should have deleted activity by alias: %v", err)
+	}
+
+	_, err = f.repo.Find("coding")
+	if err == nil {
+		t.Error("Should not find a deleted activity")
+	}
+
+	activities, _ := f.repo.List()
+	if len(activities) != 1 {
+		t.Errorf("Should have one activity left, got %d", len(activities))
+	}
+}
+
+func testDeleteUnknownActivity(t *testing.T, f *fixture) {
+	err := f.repo.Delete("coding", core.DeleteOptions{})
+	if err == nil {
+		t.Error("Should have failed to delete an unknown activity")
+	}
+}
+
+func testDeleteActivityWithLogs(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	f.track(t, coding, f.at(9, 0), time.Hour)
+
+	if f.repo.Delete("coding", core.DeleteOptions{}) == nil {
+		t.Fatal("Should not delete an activity with logs without choosing what happens to them")
+	}
+
+	mustSucceed(t, "delete with logs", f.repo.Delete("coding", core.DeleteOptions{DeleteLogs: true}))
+
+	// A new activity must not inherit the logs of the deleted one, whatever its id.
+	f.add(t, "coding", "c")
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	if len(logs) != 0 {
+		t.Errorf("Should have deleted the logs of the activity, got %v", logs)
+	}
+}
+
+func testDeleteActivityMovingLogs(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	programming := f.add(t, "programming", "p")
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, programming, f.at(11, 0), time.Hour)
+
+	if f.repo.Delete("coding", core.DeleteOptions{MoveLogsTo: &coding}) == nil {
+		t.Fatal("Should not move the logs to the deleted activity")
+	}
+
+	mustSucceed(t, "delete moving logs", f.repo.Delete("coding", core.DeleteOptions{MoveLogsTo: &programming}))
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"programming": 7200}})
+}
+
+func testArchiveAndUnarchive(t *testing.T, f *fixture) {
+	work := f.add(t, "work", "w")
+	f.add(t, "work/backend", "")
+	f.add(t, "reading", "")
+	f.track(t, work, f.at(9, 0), time.Hour)
+
+	mustSucceed(t, "archive", f.repo.Archive("w"))
+
+	activities, _ := f.repo.List()
+	for _, activity := range activities {
+		if activity.Archived != (activity.Name != "reading") {
+			t.Errorf("Only work and its sub-activities should be archived, got %+v", activity)
+		}
+	}
+
+	found, err := f.repo.Find("work")
+	if err != nil || !found.Archived {
+		t.Fatalf("Should find the archived activity, got %+v (%v)", found, err)
+	}
+
+	if f.repo.Start(*found, core.StartOptions{}) == nil {
+		t.Error("Should not start an archived activity")
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"work": 3600}})
+
+	mustSucceed(t, "unarchive", f.repo.Unarchive("work"))
+
+	backend, _ := f.repo.Find("work/backend")
+	if backend.Archived {
+		t.Error("Should have unarchived the sub-activities")
+	}
+
+	f.clock.SetNow(f.at(11, 0))
+	mustSucceed(t, "start unarchived activity", f.repo.Start(*backend, core.StartOptions{}))
+}
+
+func testArchiveTrackedActivity(t *testing.T, f *fixture) {
+	f.add(t, "work", "")
+	backend := f.add(t, "work/backend", "")
+	mustSucceed(t, "start", f.repo.Start(backend, core.StartOptions{}))
+
+	if f.repo.Archive("work") == nil {
+		t.Error("Should not archive an activity while it, or one of its sub-activities, is tracked")
+	}
+
+	if f.repo.Delete("work/backend", core.DeleteOptions{DeleteLogs: true}) == nil {
+		t.Error("Should not delete the tracked activity")
+	}
+}
+
+func testAddSubActivity(t *testing.T, f *fixture) {
+	err := f.repo.Add(core.Activity{Name: "work/backend"})
+	if err == nil {
+		t.Fatal("Should not add a sub-activity of an unknown activity")
+	}
+
+	f.add(t, "work", "")
+	reviews := f.add(t, "work/backend", "wb")
+
+	if reviews.ParentName() != "work" {
+		t.Errorf("Sub-activity should have work as parent, got %s", reviews.ParentName())
+	}
+}
+
+func testDeleteActivityWithSubActivities(t *testing.T, f *fixture) {
+	f.add(t, "work", "")
+	f.add(t, "work/backend", "")
+	f.add(t, "workout", "")
+
+	if f.repo.Delete("work", core.DeleteOptions{}) == nil {
+		t.Fatal("Should not delete an activity with sub-activities")
+	}
+
+	mustSucceed(t, "delete sub-activity", f.repo.Delete("work/backend", core.DeleteOptions{}))
+	mustSucceed(t, "delete activity", f.repo.Delete("work", core.DeleteOptions{}))
+
+	activities, _ := f.repo.List()
+	if len(activities) != 1 || activities[0].Name != "workout" {
+		t.Errorf("Should only have deleted work and its sub-activity, got %+v", activities)
+	}
+}
+
+func testRenameActivityWithSubActivities(t *testing.T, f *fixture) {
+	f.add(t, "work", "")
+	f.add(t, "work/backend", "")
+	f.add(t, "work/backend/reviews", "")
+	f.add(t, "workout", "")
+	f.add(t, "job", "")
+
+	mustSucceed(t, "rename", f.repo.Update("work/backend", core.UpdateActivityName{Name: "job/api"}))
+
+	for _, name := range []string{"job/api", "job/api/reviews", "work", "workout"} {
+		if _, err := f.repo.Find(name); err != nil {
+			t.Errorf("Should have found activity %s after renaming: %v", name, err)
+		}
+	}
+
+	if f.repo.Update("job", core.UpdateActivityName{Name: "job/api/job"}) == nil {
+		t.Error("Should not make an activity a sub-activity of itself")
+	}
+
+	if f.repo.Update("workout", core.UpdateActivityName{Name: "unknown/workout"}) == nil {
+		t.Error("Should not rename an activity under an unknown parent")
+	}
+}
+
+func testStartAndStop(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	current, err := f.repo.CurrentlyTrackedActivity()
+	if err != nil || current != nil {
+		t.Fatalf("Should not be tracking anything before starting, got %+v (%v)", current, err)
+	}
+
+	err = f.repo.Start(coding, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity: %v", err)
+	}
+
+	current, err = f.repo.CurrentlyTrackedActivity()
+	if err != nil || current == nil || current.Id != coding.Id {
+		t.Fatalf("Should be tracking the started activity, got %+v (%v)", current, err)
+	}
+
+	f.clock.SetNow(f.clock.Now().Add(time.Hour))
+	err = f.repo.Stop(coding, core.StopOptions{})
+	if err != nil {
+		t.Fatalf("Should have stopped activity: %v", err)
+	}
+
+	current, err = f.repo.CurrentlyTrackedActivity()
+	if err != nil || current != nil {
+		t.Errorf("Should not be tracking anything after stopping, got %+v (%v)", current, err)
+	}
+}
+
+func testStartWhileTrackingAnotherActivity(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	err := f.repo.Start(coding, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity: %v", err)
+	}
+
+	err = f.repo.Start(reading, core.StartOptions{})
+	if err == nil {
+		t.Error("Should not start an activity while another one is being tracked")
+	}
+
+	err = f.repo.Start(coding, core.StartOptions{})
+	if err == nil {
+		t.Error("Should not start an activity that is already being tracked")
+	}
+}
+
+func testStopWhenNotTracking(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	err := f.repo.Stop(coding, core.StopOptions{})
+	if err == nil {
+		t.Error("Should not stop an activity that is not being tracked")
+	}
+}
+
+func testStopAnotherActivity(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	err := f.repo.Start(coding, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity: %v", err)
+	}
+
+	err = f.repo.Stop(reading, core.StopOptions{})
+	if err == nil {
+		t.Error("Should not stop an activity other than the one being tracked")
+	}
+
+	current, _ := f.repo.CurrentlyTrackedActivity()
+	if current == nil || current.Id != coding.Id {
+		t.Errorf("The tracked activity should not have been stopped, got %+v", current)
+	}
+}
+
+func testStartAndStopAtGivenInstants(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, reading, base, time.Hour)
+	f.clock.SetNow(base.Add(3 * time.Hour))
+
+	startedAt := base.Add(90 * time.Minute)
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{At: &startedAt}))
+	stoppedAt := base.Add(150 * time.Minute)
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{At: &stoppedAt}))
+
+	restartedAt := base.Add(155 * time.Minute)
+	mustSucceed(t, "start again", f.repo.Start(coding, core.StartOptions{At: &restartedAt}))
+	switchedAt := base.Add(160 * time.Minute)
+	mustSucceed(t, "switch", f.repo.Switch(reading, core.StartOptions{At: &switchedAt}))
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != reading.Id || !current.StartedAt.Equal(switchedAt) {
+		t.Fatalf("Should be tracking the activity switched to since the given instant, got %+v", current)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600 + 300, "reading": 3600}})
+}
+
+func testStartAndStopAtInvalidInstants(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, reading, base, time.Hour)
+	f.clock.SetNow(base.Add(3 * time.Hour))
+
+	duringSession := base.Add(30 * time.Minute)
+	if f.repo.Start(coding, core.StartOptions{At: &duringSession}) == nil {
+		t.Error("Should not start during another session")
+	}
+
+	future := base.Add(4 * time.Hour)
+	if f.repo.Start(coding, core.StartOptions{At: &future}) == nil {
+		t.Error("Should not start in the future")
+	}
+
+	startedAt := base.Add(2 * time.Hour)
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{At: &startedAt}))
+	beforeStart := base.Add(90 * time.Minute)
+	if f.repo.Stop(coding, core.StopOptions{At: &beforeStart}) == nil {
+		t.Error("Should not stop before the session started")
+	}
+
+	if f.repo.Stop(coding, core.StopOptions{At: &future}) == nil {
+		t.Error("Should not stop in the future")
+	}
+
+	if f.repo.Switch(reading, core.StartOptions{At: &beforeStart}) == nil {
+		t.Error("Should not switch before the running session started")
+	}
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != coding.Id || !current.StartedAt.Equal(startedAt) {
+		t.Errorf("Refused changes should leave the running session unchanged, got %+v", current)
+	}
+}
+
+func testSwitch(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+
+	mustSucceed(t, "switch without tracking", f.repo.Switch(coding, core.StartOptions{}))
+	f.clock.SetNow(base.Add(time.Hour))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(base.Add(90 * time.Minute))
+	mustSucceed(t, "switch", f.repo.Switch(reading, core.StartOptions{Note: "chapter 2", Tags: []string{"books"}}))
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != reading.Id || !current.StartedAt.Equal(base.Add(90*time.Minute)) || current.Note != "chapter 2" || len(current.Tags) != 1 {
+		t.Fatalf("Should be tracking the new activity since the switch, got %+v", current)
+	}
+
+	sessions, _ := f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), &coding)
+	if len(sessions) != 1 || sessions[0].StoppedAt == nil || !sessions[0].StoppedAt.Equal(*current.StartedAt) {
+		t.Fatalf("The previous session should stop when the new one starts, got %+v", sessions)
+	}
+
+	f.clock.SetNow(base.Add(2 * time.Hour))
+	mustSucceed(t, "stop", f.repo.Stop(reading, core.StopOptions{}))
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600, "reading": 1800}})
+}
+
+func testSwitchWhenNotAllowed(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	mustSucceed(t, "archive", f.repo.Archive("reading"))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+
+	if f.repo.Switch(coding, core.StartOptions{}) == nil {
+		t.Error("Should not switch to the activity being tracked")
+	}
+
+	if f.repo.Switch(reading, core.StartOptions{}) == nil {
+		t.Error("Should not switch to an archived activity")
+	}
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != coding.Id || current.StoppedAt != nil {
+		t.Errorf("A refused switch should leave the tracked session running, got %+v", current)
+	}
+}
+
+func testSessionStoppedAtPlannedEnd(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	plannedEnd := base.Add(45 * time.Minute)
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Until: &plannedEnd}))
+
+	f.clock.SetNow(base.Add(30 * time.Minute))
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.PlannedEnd == nil || !current.PlannedEnd.Equal(plannedEnd) {
+		t.Fatalf("Should be tracking the session until its planned end, got %+v", current)
+	}
+	if remaining, ok := current.Remaining(f.clock.Now()); !ok || remaining != 15*time.Minute {
+		t.Errorf("Should have 15 minutes left, got %v", remaining)
+	}
+
+	f.clock.SetNow(base.Add(time.Hour))
+	tracked, _ := f.repo.CurrentlyTrackedActivity()
+	if tracked != nil {
+		t.Fatalf("Should not be tracking the session past its planned end, got %+v", tracked)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 2700}})
+
+	last, _ := f.repo.LastSession()
+	if last == nil || last.StoppedAt == nil || !last.StoppedAt.Equal(plannedEnd) {
+		t.Errorf("The last session should be read as stopped at its planned end, got %+v", last)
+	}
+
+	stored, _ := f.repo.CurrentActivityLogs(base)
+	if len(stored) != 1 || stored[0].StoppedAt != nil {
+		t.Errorf("Reading the sessions should not stop them, got %+v", stored)
+	}
+
+	mustSucceed(t, "start after the planned end", f.repo.Start(reading, core.StartOptions{}))
+	sessions, _ := f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), &coding)
+	if len(sessions) != 1 || sessions[0].StoppedAt == nil || !sessions[0].StoppedAt.Equal(plannedEnd) {
+		t.Errorf("The session should stop at its planned end, got %+v", sessions)
+	}
+
+	stored, _ = f.repo.CurrentActivityLogs(base)
+	if len(stored) != 1 || stored[0].Activity.Name != "reading" {
+		t.Errorf("Starting an activity should stop the session past its planned end, got %+v", stored)
+	}
+}
+
+func testCurrentActivityLogs(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	midnight := f.at(0, 0)
+	f.track(t, coding, midnight.Add(-14*time.Hour), time.Hour)
+	f.track(t, coding, midnight.Add(9*time.Hour), time.Hour)
+	f.track(t, reading, midnight.Add(10*time.Hour), 30*time.Minute)
+
+	if logs, _ := f.repo.CurrentActivityLogs(midnight); len(logs) != 0 {
+		t.Fatalf("Should have no current logs while not tracking, got %+v", logs)
+	}
+
+	f.clock.SetNow(midnight.Add(11 * time.Hour))
+	plannedEnd := midnight.Add(13 * time.Hour)
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Until: &plannedEnd}))
+	f.clock.SetNow(midnight.Add(11*time.Hour + 30*time.Minute))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(midnight.Add(11*time.Hour + 45*time.Minute))
+	mustSucceed(t, "resume", f.repo.Resume())
+
+	f.clock.SetNow(midnight.Add(14 * time.Hour))
+	logs, err := f.repo.CurrentActivityLogs(midnight)
+	if err != nil {
+		t.Fatalf("Should have returned the current logs: %v", err)
+	}
+	if len(logs) != 2 || !logs[0].StartedAt.Equal(midnight.Add(9*time.Hour)) || logs[0].Activity.Name != "coding" {
+		t.Fatalf("Should return the sessions of the tracked activity since midnight, got %+v", logs)
+	}
+
+	running := logs[1]
+	if running.StoppedAt != nil || running.PlannedEnd == nil || !running.PlannedEnd.Equal(plannedEnd) {
+		t.Errorf("Should return the session past its planned end as running, got %+v", running)
+	}
+	if len(running.Pauses) != 1 || !running.Pauses[0].ResumedAt.Equal(midnight.Add(11*time.Hour+45*time.Minute)) {
+		t.Errorf("Should return the pauses of the sessions, got %+v", running.Pauses)
+	}
+	if running.Activity.Alias != "c" || running.Activity.Description != "coding description" {
+		t.Errorf("Should return the activity of the sessions, got %+v", running.Activity)
+	}
+}
+
+func testConcurrentActivity(t *testing.T, f *fixture) {
+	f.add(t, "on-call", "o")
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	mustSucceed(t, "update", f.repo.Update("on-call", core.UpdateActivityConcurrent{Concurrent: true}))
+	onCall, _ := f.repo.Find("on-call")
+	if !onCall.Concurrent {
+		t.Fatalf("The activity should be concurrent, got %+v", onCall)
+	}
+
+	base := f.at(9, 0)
+	mustSucceed(t, "start concurrent activity", f.repo.Start(*onCall, core.StartOptions{}))
+	f.clock.SetNow(base.Add(time.Hour))
+	mustSucceed(t, "start alongside concurrent activity", f.repo.Start(coding, core.StartOptions{}))
+	if f.repo.Start(reading, core.StartOptions{}) == nil {
+		t.Error("Should not start alongside an activity that is not concurrent")
+	}
+
+	sessions, _ := f.repo.CurrentSessions()
+	if len(sessions) != 2 || sessions[0].Activity.Id != onCall.Id || sessions[1].Activity.Id != coding.Id {
+		t.Fatalf("Should be tracking both activities, got %+v", sessions)
+	}
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != coding.Id {
+		t.Errorf("The current session should be the one started last, got %+v", current)
+	}
+
+	f.clock.SetNow(base.Add(2 * time.Hour))
+	mustSucceed(t, "switch", f.repo.Switch(reading, core.StartOptions{}))
+	f.clock.SetNow(base.Add(3 * time.Hour))
+	mustSucceed(t, "stop concurrent activity", f.repo.Stop(*onCall, core.StopOptions{}))
+	if f.repo.Stop(coding, core.StopOptions{}) == nil {
+		t.Error("Should not stop an activity that is not tracked")
+	}
+
+	sessions, _ = f.repo.CurrentSessions()
+	if len(sessions) != 1 || sessions[0].Activity.Id != reading.Id {
+		t.Fatalf("Should only be tracking the activity switched to, got %+v", sessions)
+	}
+	mustSucceed(t, "stop", f.repo.Stop(reading, core.StopOptions{}))
+
+	period := mustPeriod(t, "2020-10-10", "2020-10-10")
+	logs, _ := f.repo.LogsForPeriod(period, core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"on-call": 3 * 3600, "coding": 3600, "reading": 3600}})
+
+	logs, _ = f.repo.LogsForPeriod(period, core.LogFilter{Overlap: core.SplitOverlap})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"on-call": 3600 + 1800 + 1800, "coding": 1800, "reading": 1800}})
+}
+
+func testConcurrentTimersForAllActivities(t *testing.T, f *fixture) {
+	repo := f.factory(t, f.clock, config.Config{Location: time.Local, ConcurrentTimers: true})
+	defer repo.Shutdown()
+	f = &fixture{repo: repo, clock: f.clock, factory: f.factory}
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+	f.clock.SetNow(f.at(9, 30))
+	mustSucceed(t, "start another activity", f.repo.Start(reading, core.StartOptions{}))
+	if f.repo.Start(coding, core.StartOptions{}) == nil {
+		t.Error("Should not track the same activity twice at once")
+	}
+
+	f.clock.SetNow(f.at(10, 0))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(f.at(10, 30))
+	mustSucceed(t, "resume", f.repo.Resume())
+	for _, session := range mustCurrentSessions(t, f.repo) {
+		if len(session.Pauses) != 1 || session.IsPaused() {
+			t.Errorf("All the running sessions should have been paused and resumed, got %+v", session)
+		}
+	}
+
+	f.clock.SetNow(f.at(11, 0))
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{}))
+	if sessions := mustCurrentSessions(t, f.repo); len(sessions) != 1 || sessions[0].Activity.Id != reading.Id {
+		t.Fatalf("Should still be tracking the other activity, got %+v", sessions)
+	}
+
+	f.clock.SetNow(f.at(11, 30))
+	mustSucceed(t, "stop", f.repo.Stop(reading, core.StopOptions{}))
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 5400, "reading": 5400}})
+}
+
+func testSplitOverlapOfFilteredLogs(t *testing.T, f *fixture) {
+	repo := f.factory(t, f.clock, config.Config{Location: time.Local, ConcurrentTimers: true})
+	defer repo.Shutdown()
+	f = &fixture{repo: repo, clock: f.clock, factory: f.factory}
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	f.clock.SetNow(f.at(9, 0))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Tags: []string{"billable"}}))
+	f.clock.SetNow(f.at(9, 30))
+	mustSucceed(t, "start another activity", f.repo.Start(reading, core.StartOptions{}))
+	f.clock.SetNow(f.at(10, 0))
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{}))
+	f.clock.SetNow(f.at(10, 30))
+	mustSucceed(t, "stop another activity", f.repo.Stop(reading, core.StopOptions{}))
+
+	period := mustPeriod(t, "2020-10-10", "2020-10-10")
+	logs, _ := f.repo.LogsForPeriod(period, core.LogFilter{Tags: []string{"billable"}, Overlap: core.SplitOverlap})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 1800 + 900}})
+
+	logs, _ = f.repo.LogsForPeriod(period, core.LogFilter{ExcludedTags: []string{"billable"}, Overlap: core.SplitOverlap})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"reading": 900 + 1800}})
+}
+
+func testStartWithInvalidPlannedEnd(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.clock.SetNow(base.Add(time.Hour))
+
+	startedAt := base.Add(30 * time.Minute)
+	if f.repo.Start(coding, core.StartOptions{At: &startedAt, Until: &base}) == nil {
+		t.Error("Should not plan a session to end before it starts")
+	}
+
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+	now := f.clock.Now()
+	if f.repo.Switch(reading, core.StartOptions{Until: &now}) == nil {
+		t.Error("Should not plan a session to end when it starts")
+	}
+
+	current, _ := f.repo.CurrentSession()
+	if current == nil || current.Activity.Id != coding.Id || current.PlannedEnd != nil {
+		t.Errorf("Refused starts should leave the running session unchanged, got %+v", current)
+	}
+}
+
+func testLogsForPeriodAggregatesPerDay(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, reading, f.at(10, 0), 30*time.Minute)
+	f.track(t, coding, f.at(11, 0), 15*time.Minute)
+	f.track(t, coding, f.at(9, 0).AddDate(0, 0, 1), 2*time.Hour)
+	f.track(t, coding, f.at(9, 0).AddDate(0, 0, 5), time.Hour)
+
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-12")
+	logs, err := f.repo.LogsForPeriod(period, core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	expected := map[string]map[string]int{
+		"2020-10-10": {"coding": 4500, "reading": 1800},
+		"2020-10-11": {"coding": 7200},
+	}
+
+	assertAggregations(t, logs, expected)
+}
+
+func testLogsForPeriodIgnoresRunningSessions(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.clock.SetNow(f.at(11, 0))
+	err := f.repo.Start(coding, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity: %v", err)
+	}
+	f.clock.SetNow(f.at(12, 0))
+
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	logs, err := f.repo.LogsForPeriod(period, core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600}})
+}
+
+func testSessionCrossingMidnight(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.clock.SetNow(f.at(23, 30))
+	err := f.repo.Start(coding, core.StartOptions{})
+	if err != nil {
+		t.Fatalf("Should have started activity: %v", err)
+	}
+
+	f.clock.SetNow(f.at(23, 30).Add(2 * time.Hour))
+	current, err := f.repo.CurrentlyTrackedActivity()
+	if err != nil || current == nil || current.Id != coding.Id {
+		t.Fatalf("Should still be tracking the activity after midnight, got %+v (%v)", current, err)
+	}
+
+	err = f.repo.Stop(coding, core.StopOptions{})
+	if err != nil {
+		t.Fatalf("Should have stopped the activity after midnight: %v", err)
+	}
+
+	current, _ = f.repo.CurrentlyTrackedActivity()
+	if current != nil {
+		t.Errorf("Should not be tracking anything after stopping, got %+v", current)
+	}
+}
+
+func testLogsForPeriodSplitsSessionsAcrossDays(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.track(t, coding, f.at(23, 30), 25*time.Hour)
+
+	logs, err := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-12"), core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	assertAggregations(t, logs, map[string]map[string]int{
+		"2020-10-10": {"coding": 1800},
+		"2020-10-11": {"coding": 86400},
+		"2020-10-12": {"coding": 1800},
+	})
+
+	logs, err = f.repo.LogsForPeriod(mustPeriod(t, "2020-10-11", "2020-10-11"), core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-11": {"coding": 86400}})
+}
+
+func testLogsForPeriodUsesConfiguredTimeZone(t *testing.T, f *fixture) {
+	f = newFixture(t, f.factory, time.FixedZone("UTC+5", 5*60*60))
+	defer f.repo.Shutdown()
+	coding := f.add(t, "coding", "c")
+
+	// 20:00 UTC is already 01:00 of the next day in the repository time zone.
+	f.track(t, coding, time.Date(2020, 10, 10, 18, 0, 0, 0, time.UTC), 3*time.Hour)
+
+	logs, err := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-11"), core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	assertAggregations(t, logs, map[string]map[string]int{
+		"2020-10-10": {"coding": 3600},
+		"2020-10-11": {"coding": 7200},
+	})
+
+	err = f.repo.WipeLogsPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"))
+	if err != nil {
+		t.Fatalf("Should have wiped logs: %v", err)
+	}
+
+	logs, _ = f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-11"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{})
+}
+
+func testLogSession(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	base := f.at(9, 0)
+	f.clock.SetNow(base.Add(12 * time.Hour))
+
+	interval := core.Interval{Start: base.Add(-time.Hour), End: base.Add(30 * time.Minute)}
+	mustSucceed(t, "log session", f.repo.LogSession(coding, interval, core.StartOptions{Note: "forgot the timer", Tags: []string{"billable"}}))
+
+	if current, _ := f.repo.CurrentSession(); current != nil {
+		t.Errorf("A logged session should not be running, got %+v", current)
+	}
+
+	last, _ := f.repo.LastSession()
+	if last == nil || !last.StartedAt.Equal(interval.Start) || !last.StoppedAt.Equal(interval.End) || last.Note != "forgot the timer" {
+		t.Fatalf("Last session should be the logged one, got %+v", last)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{Tags: []string{"billable"}})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 5400}})
+
+	future := core.Interval{Start: base.Add(11 * time.Hour), End: base.Add(13 * time.Hour)}
+	if f.repo.LogSession(coding, future, core.StartOptions{}) == nil {
+		t.Error("Should not log a session stopping in the future")
+	}
+}
+
+func testLogSessionOverlappingAnotherOne(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+
+	overlapping := core.Interval{Start: base.Add(30 * time.Minute), End: base.Add(90 * time.Minute)}
+	if f.repo.LogSession(reading, overlapping, core.StartOptions{}) == nil {
+		t.Error("Should not log a session overlapping a tracked one")
+	}
+
+	f.clock.SetNow(base.Add(3 * time.Hour))
+	mustSucceed(t, "start", f.repo.Start(reading, core.StartOptions{}))
+	f.clock.SetNow(base.Add(5 * time.Hour))
+
+	duringRunning := core.Interval{Start: base.Add(4 * time.Hour), End: base.Add(4*time.Hour + 30*time.Minute)}
+	if f.repo.LogSession(coding, duringRunning, core.StartOptions{}) == nil {
+		t.Error("Should not log a session overlapping the running one")
+	}
+
+	adjacent := core.Interval{Start: base.Add(time.Hour), End: base.Add(2 * time.Hour)}
+	mustSucceed(t, "log adjacent session", f.repo.LogSession(coding, adjacent, core.StartOptions{}))
+}
+
+func testSessionsForPeriod(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, coding, base.Add(-24*time.Hour), time.Hour)
+	f.track(t, coding, base, time.Hour)
+	f.track(t, reading, base.Add(2*time.Hour), time.Hour)
+	f.clock.SetNow(base.Add(4 * time.Hour))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+
+	sessions, err := f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), nil)
+	mustSucceed(t, "list sessions", err)
+
+	if len(sessions) != 3 || !sessions[0].StartedAt.Equal(base) || sessions[1].Activity.Name != "reading" || !sessions[2].IsRunning() {
+		t.Fatalf("Should list the sessions of the day, running included, ordered by start, got %+v", sessions)
+	}
+
+	sessions, _ = f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-09", "2020-10-10"), &reading)
+	if len(sessions) != 1 || sessions[0].Activity.Name != "reading" {
+		t.Errorf("Should only list the sessions of the activity, got %+v", sessions)
+	}
+
+	found, err := f.repo.FindSession(sessions[0].Id)
+	if err != nil || !found.StartedAt.Equal(base.Add(2*time.Hour)) || found.Activity.Name != "reading" {
+		t.Errorf("Should find the session by its id, got %+v (%v)", found, err)
+	}
+
+	if _, err := f.repo.FindSession(sessions[0].Id + 100); err == nil {
+		t.Error("Should not find an unknown session")
+	}
+}
+
+func testUpdateSession(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+	f.clock.SetNow(base.Add(24 * time.Hour))
+
+	session, _ := f.repo.LastSession()
+	newStart := base.Add(-30 * time.Minute)
+	newStop := base.Add(2 * time.Hour)
+	mustSucceed(t, "update", f.repo.UpdateSession(session.Id, core.SessionUpdate{StartedAt: &newStart, StoppedAt: &newStop, Activity: &reading}))
+
+	updated, _ := f.repo.FindSession(session.Id)
+	if !updated.StartedAt.Equal(newStart) || !updated.StoppedAt.Equal(newStop) || updated.Activity.Name != "reading" {
+		t.Errorf("Session was not correctly updated: %+v", updated)
+	}
+
+	previousDay := base.Add(-10 * time.Hour)
+	mustSucceed(t, "move to previous day", f.repo.UpdateSession(session.Id, core.SessionUpdate{StartedAt: &previousDay}))
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-09", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-09": {"reading": 3600}, "2020-10-10": {"reading": 11 * 3600}})
+}
+
+func testUpdateSessionWithInvalidChanges(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+	f.track(t, coding, base.Add(2*time.Hour), time.Hour)
+	f.clock.SetNow(base.Add(12 * time.Hour))
+
+	session, _ := f.repo.LastSession()
+	overlapping := base.Add(30 * time.Minute)
+	if f.repo.UpdateSession(session.Id, core.SessionUpdate{StartedAt: &overlapping}) == nil {
+		t.Error("Should not make a session overlap another one")
+	}
+
+	beforeStart := base.Add(time.Hour + 30*time.Minute)
+	if f.repo.UpdateSession(session.Id, core.SessionUpdate{StoppedAt: &beforeStart}) == nil {
+		t.Error("Should not make a session stop before it starts")
+	}
+
+	unchanged, _ := f.repo.FindSession(session.Id)
+	if !unchanged.StartedAt.Equal(base.Add(2*time.Hour)) || !unchanged.StoppedAt.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("Refused changes should leave the session unchanged, got %+v", unchanged)
+	}
+
+	if f.repo.UpdateSession(session.Id+100, core.SessionUpdate{}) == nil {
+		t.Error("Should not update an unknown session")
+	}
+}
+
+func testDeleteSession(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+	f.track(t, coding, base.Add(2*time.Hour), time.Hour)
+
+	session, _ := f.repo.LastSession()
+	mustSucceed(t, "delete session", f.repo.DeleteSession(session.Id))
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600}})
+
+	if f.repo.DeleteSession(session.Id) == nil {
+		t.Error("Should not delete an unknown session")
+	}
+}
+
+func testApplySessionChanges(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+	f.track(t, coding, base.Add(2*time.Hour), time.Hour)
+	f.clock.SetNow(base.Add(12 * time.Hour))
+
+	sessions, _ := f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), nil)
+	moved := sessions[1]
+	movedStart := base.Add(30 * time.Minute)
+	movedStop := base.Add(90 * time.Minute)
+	moved.StartedAt = &movedStart
+	moved.StoppedAt = &movedStop
+	moved.Activity = reading
+	moved.Note = "moved"
+	insertedStart := base.Add(4 * time.Hour)
+	insertedStop := base.Add(5 * time.Hour)
+	changes := core.SessionChanges{
+		Deletes: []int{sessions[0].Id},
+		Updates: []core.ActivityLog{moved},
+		Inserts: []core.ActivityLog{{StartedAt: &insertedStart, StoppedAt: &insertedStop, Activity: coding, Note: "added"}},
+	}
+	mustSucceed(t, "apply changes", f.repo.ApplySessionChanges(changes))
+
+	sessions, _ = f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), nil)
+	if len(sessions) != 2 || sessions[0].Id != moved.Id || sessions[0].Activity.Name != "reading" || sessions[0].Note != "moved" || sessions[1].Note != "added" {
+		t.Fatalf("Changes were not correctly applied, got %+v", sessions)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"reading": 3600, "coding": 3600}})
+}
+
+func testApplyInvalidSessionChanges(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	base := f.at(9, 0)
+	f.track(t, coding, base, time.Hour)
+	f.track(t, coding, base.Add(2*time.Hour), time.Hour)
+	f.clock.SetNow(base.Add(12 * time.Hour))
+
+	sessions, _ := f.repo.SessionsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), nil)
+	overlappingStart := base.Add(30 * time.Minute)
+	overlappingStop := base.Add(90 * time.Minute)
+	changes := core.SessionChanges{
+		Deletes: []int{sessions[1].Id},
+		Inserts: []core.ActivityLog{{StartedAt: &overlappingStart, StoppedAt: &overlappingStop, Activity: coding}},
+	}
+	if f.repo.ApplySessionChanges(changes) == nil {
+		t.Error("Should not insert a session overlapping another one")
+	}
+
+	if f.repo.ApplySessionChanges(core.SessionChanges{Deletes: []int{sessions[1].Id + 100}}) == nil {
+		t.Error("Should not delete an unknown session")
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 2 * 3600}})
+}
+
+func testPauseAndResume(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.clock.SetNow(f.at(9, 0))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+	f.clock.SetNow(f.at(9, 30))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(f.at(10, 0))
+
+	session, err := f.repo.CurrentSession()
+	if err != nil || session == nil {
+		t.Fatalf("Should have a current session while paused, got %+v (%v)", session, err)
+	}
+
+	if !session.IsPaused() || session.Activity.Id != coding.Id {
+		t.Errorf("Current session should be paused: %+v", session)
+	}
+
+	if session.Elapsed(f.clock.Now()) != 30*time.Minute {
+		t.Errorf("Paused time should not count as elapsed, got %v", session.Elapsed(f.clock.Now()))
+	}
+
+	mustSucceed(t, "resume", f.repo.Resume())
+	f.clock.SetNow(f.at(10, 15))
+
+	session, _ = f.repo.CurrentSession()
+	if session == nil || session.IsPaused() {
+		t.Fatalf("Current session should be running after resuming, got %+v", session)
+	}
+
+	if session.Elapsed(f.clock.Now()) != 45*time.Minute {
+		t.Errorf("Should have 45 minutes elapsed after resuming, got %v", session.Elapsed(f.clock.Now()))
+	}
+}
+
+func testPauseAndResumeWhenNotAllowed(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	if f.repo.Pause() == nil {
+		t.Error("Should not pause when nothing is being tracked")
+	}
+
+	if f.repo.Resume() == nil {
+		t.Error("Should not resume when nothing is being tracked")
+	}
+
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+
+	if f.repo.Resume() == nil {
+		t.Error("Should not resume an activity that is not paused")
+	}
+
+	mustSucceed(t, "pause", f.repo.Pause())
+
+	if f.repo.Pause() == nil {
+		t.Error("Should not pause an activity that is already paused")
+	}
+}
+
+func testStopWhilePaused(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.clock.SetNow(f.at(9, 0))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+	f.clock.SetNow(f.at(10, 0))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(f.at(11, 0))
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{}))
+
+	session, _ := f.repo.CurrentSession()
+	if session != nil {
+		t.Errorf("Should not have a current session after stopping, got %+v", session)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600}})
+}
+
+func testLogsForPeriodSubtractsPauses(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	startedAt := f.at(23, 0)
+	f.clock.SetNow(startedAt)
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{}))
+	f.clock.SetNow(startedAt.Add(30 * time.Minute))
+	mustSucceed(t, "pause", f.repo.Pause())
+	f.clock.SetNow(startedAt.Add(90 * time.Minute))
+	mustSucceed(t, "resume", f.repo.Resume())
+	f.clock.SetNow(startedAt.Add(150 * time.Minute))
+	mustSucceed(t, "pause", f.repo.Pause())
+	mustSucceed(t, "resume", f.repo.Resume())
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{}))
+
+	logs, err := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-11"), core.LogFilter{})
+	if err != nil {
+		t.Fatalf("Should have fetched logs for period: %v", err)
+	}
+
+	assertAggregations(t, logs, map[string]map[string]int{
+		"2020-10-10": {"coding": 1800},
+		"2020-10-11": {"coding": 3600},
+	})
+}
+
+func testSessionNotes(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	f.clock.SetNow(f.at(9, 0))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Note: "refactor parser"}))
+
+	session, _ := f.repo.CurrentSession()
+	if session == nil || session.Note != "refactor parser" {
+		t.Fatalf("Current session should have the start note, got %+v", session)
+	}
+
+	f.clock.SetNow(f.at(10, 0))
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{Note: "added tests"}))
+	f.track(t, coding, f.at(11, 0), time.Hour)
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	notes := logs["2020-10-10"][0].Notes
+	if len(notes) != 1 || notes[0] != "refactor parser; added tests" {
+		t.Errorf("Aggregation should have the notes of its sessions, got %v", notes)
+	}
+}
+
+func testSetNoteOfLastSession(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+
+	last, err := f.repo.LastSession()
+	if err != nil || last != nil {
+		t.Fatalf("Should not have a last session before tracking, got %+v (%v)", last, err)
+	}
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, coding, f.at(11, 0), time.Hour)
+
+	last, err = f.repo.LastSession()
+	if err != nil || last == nil || !last.StartedAt.Equal(f.at(11, 0)) {
+		t.Fatalf("Last session should be the one started at 11:00, got %+v (%v)", last, err)
+	}
+
+	mustSucceed(t, "set note", f.repo.SetNote(last.Id, "code review"))
+
+	last, _ = f.repo.LastSession()
+	if last.Note != "code review" {
+		t.Errorf("Should have set the note of the last session, got %q", last.Note)
+	}
+
+	if f.repo.SetNote(last.Id+100, "nothing") == nil {
+		t.Error("Should not set the note of an unknown session")
+	}
+}
+
+func testTagAndUntagActivity(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	f.add(t, "reading", "")
+
+	mustSucceed(t, "tag", f.repo.TagActivity(coding, []string{"work", "billable"}))
+	mustSucceed(t, "tag again", f.repo.TagActivity(coding, []string{"work"}))
+
+	found, _ := f.repo.Find("c")
+	if !reflect.DeepEqual(found.Tags, []string{"billable", "work"}) {
+		t.Errorf("Activity should have the tags sorted and without duplicates, got %v", found.Tags)
+	}
+
+	activities, _ := f.repo.List()
+	for _, activity := range activities {
+		if activity.Name == "reading" && len(activity.Tags) != 0 {
+			t.Errorf("Untagged activity should have no tags, got %v", activity.Tags)
+		}
+	}
+
+	mustSucceed(t, "untag", f.repo.UntagActivity(coding, []string{"billable"}))
+
+	found, _ = f.repo.Find("coding")
+	if !reflect.DeepEqual(found.Tags, []string{"work"}) {
+		t.Errorf("Should have removed the tag, got %v", found.Tags)
+	}
+
+	tags, _ := f.repo.Tags()
+	if !reflect.DeepEqual(tags, []string{"work"}) {
+		t.Errorf("Should only list the tags in use, got %v", tags)
+	}
+}
+
+func testSessionTags(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	mustSucceed(t, "tag", f.repo.TagActivity(coding, []string{"work"}))
+	coding.Tags = []string{"work"}
+
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Tags: []string{"clienta", "billable"}}))
+
+	session, _ := f.repo.CurrentSession()
+	if session == nil || !reflect.DeepEqual(session.Tags, []string{"billable", "clienta"}) {
+		t.Fatalf("Session should have its own tags, got %+v", session)
+	}
+
+	if !reflect.DeepEqual(session.AllTags(), []string{"billable", "clienta", "work"}) {
+		t.Errorf("Session should also have the tags of its activity, got %v", session.AllTags())
+	}
+
+	tags, _ := f.repo.Tags()
+	if !reflect.DeepEqual(tags, []string{"billable", "clienta", "work"}) {
+		t.Errorf("Should list the tags of sessions and activities, got %v", tags)
+	}
+}
+
+func testLogsForPeriodFilteredByTags(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	mustSucceed(t, "tag", f.repo.TagActivity(reading, []string{"personal"}))
+
+	f.clock.SetNow(f.at(9, 0))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Tags: []string{"billable"}}))
+	f.clock.SetNow(f.at(10, 0))
+	mustSucceed(t, "stop", f.repo.Stop(coding, core.StopOptions{}))
+	f.track(t, coding, f.at(11, 0), 30*time.Minute)
+
+	f.clock.SetNow(f.at(12, 0))
+	mustSucceed(t, "start", f.repo.Start(reading, core.StartOptions{Tags: []string{"billable"}}))
+	f.clock.SetNow(f.at(12, 15))
+	mustSucceed(t, "stop", f.repo.Stop(reading, core.StopOptions{}))
+
+	period := mustPeriod(t, "2020-10-10", "2020-10-10")
+
+	logs, err := f.repo.LogsForPeriod(period, core.LogFilter{Tags: []string{"billable"}})
+	mustSucceed(t, "filter by tag", err)
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600, "reading": 900}})
+
+	logs, err = f.repo.LogsForPeriod(period, core.LogFilter{Tags: []string{"billable"}, ExcludedTags: []string{"personal"}})
+	mustSucceed(t, "filter by tag and excluded tag", err)
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600}})
+
+	logs, _ = f.repo.LogsForPeriod(period, core.LogFilter{})
+	for _, aggregation := range logs["2020-10-10"] {
+		if aggregation.Activity.Name == "reading" && !reflect.DeepEqual(aggregation.TagDurations, map[string]int{"billable": 900, "personal": 900}) {
+			t.Errorf("Wrong durations per tag for reading: %v", aggregation.TagDurations)
+		}
+	}
+}
+
+func testRenameTag(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	mustSucceed(t, "tag", f.repo.TagActivity(coding, []string{"client-a", "clienta"}))
+	mustSucceed(t, "start", f.repo.Start(coding, core.StartOptions{Tags: []string{"client-a"}}))
+
+	mustSucceed(t, "rename", f.repo.RenameTag("client-a", "clienta"))
+
+	found, _ := f.repo.Find("coding")
+	if !reflect.DeepEqual(found.Tags, []string{"clienta"}) {
+		t.Errorf("Should have merged the renamed tag into the existing one, got %v", found.Tags)
+	}
+
+	session, _ := f.repo.CurrentSession()
+	if !reflect.DeepEqual(session.Tags, []string{"clienta"}) {
+		t.Errorf("Should have renamed the session tag, got %v", session.Tags)
+	}
+
+	if f.repo.RenameTag("unknown", "other") == nil {
+		t.Error("Should not rename an unknown tag")
+	}
+}
+
+func testLogsForExactPeriod(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+	base := f.at(9, 0)
+
+	f.track(t, coding, base.Add(-2*time.Hour), 2*time.Hour)
+	f.track(t, reading, base.Add(time.Hour), time.Hour)
+	f.track(t, coding, base.Add(3*time.Hour), time.Hour)
+
+	period := core.Period{Sd: base.Add(-30 * time.Minute), Ed: base.Add(210 * time.Minute), Exact: true}
+	logs, err := f.repo.LogsForPeriod(period, core.LogFilter{})
+	mustSucceed(t, "logs for period", err)
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600, "reading": 3600}})
+
+	sessions, _ := f.repo.SessionsForPeriod(period, nil)
+	if len(sessions) != 3 {
+		t.Errorf("Should list the sessions partially inside the period, got %+v", sessions)
+	}
+}
+
+func testWipeLogsPeriod(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, reading, f.at(10, 0), time.Hour)
+	f.track(t, coding, f.at(9, 0).AddDate(0, 0, 1), time.Hour)
+
+	err := f.repo.WipeLogsPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"))
+	if err != nil {
+		t.Fatalf("Should have wiped logs: %v", err)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-01", "2020-10-31"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-11": {"coding": 3600}})
+}
+
+func testWipeLogsExactPeriod(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, reading, f.at(10, 30), time.Hour)
+
+	err := f.repo.WipeLogsPeriod(core.Period{Sd: f.at(10, 0), Ed: f.at(12, 0), Exact: true})
+	if err != nil {
+		t.Fatalf("Should have wiped logs: %v", err)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-10", "2020-10-10"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{"2020-10-10": {"coding": 3600}})
+}
+
+func testWipeLogsPeriodAndActivity(t *testing.T, f *fixture) {
+	coding := f.add(t, "coding", "c")
+	reading := f.add(t, "reading", "r")
+
+	f.track(t, coding, f.at(9, 0), time.Hour)
+	f.track(t, reading, f.at(10, 0), time.Hour)
+	f.track(t, coding, f.at(9, 0).AddDate(0, 0, 1), time.Hour)
+
+	err := f.repo.WipeLogsPeriodAndActivity(mustPeriod(t, "2020-10-10", "2020-10-10"), &coding)
+	if err != nil {
+		t.Fatalf("Should have wiped logs: %v", err)
+	}
+
+	logs, _ := f.repo.LogsForPeriod(mustPeriod(t, "2020-10-01", "2020-10-31"), core.LogFilter{})
+	assertAggregations(t, logs, map[string]map[string]int{
+		"2020-10-10": {"reading": 3600},
+		"2020-10-11": {"coding": 3600},
+	})
+}
+
+func mustSucceed(t *testing.T, operation string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Should have succeeded to %s: %v", operation, err)
+	}
+}
+
+func mustCurrentSessions(t *testing.T, repo core.ActivityRepository) []core.ActivityLog {
+	t.Helper()
+	sessions, err := repo.CurrentSessions()
+	if err != nil {
+		t.Fatalf("Should have returned the current sessions: %v", err)
+	}
+	return sessions
+}
+
+func mustPeriod(t *testing.T, sd, ed string) core.Period {
+	t.Helper()
+	period, err := core.PeriodFromDateStrings(sd, ed)
+	if err != nil {
+		t.Fatalf("Invalid period %s - %s: %v", sd, ed, err)
+	}
+	return period
+}
+
+// assertAggregations compares the durations per day and activity name, ignoring the order of the aggregations.
+func assertAggregations(t *testing.T, logs map[string][]core.ActivityDurationDayAggregation, expected map[string]map[string]int) {
+	t.Helper()
+	actual := make(map[string]map[string]int)
+	for day, aggregations := range logs {
+		for _, aggregation := range aggregations {
+			if aggregation.Date != day {
+				t.Errorf("Aggregation for %s is keyed under day %s", aggregation.Date, day)
+			}
+			if actual[day] == nil {
+				actual[day] = make(map[string]int)
+			}
+			actual[day][aggregation.Activity.Name] += aggregation.Duration
+		}
+	}
+
+	if len(actual) != len(expected) {
+		t.Errorf("Expected logs for %d days, got %d: %v", len(expected), len(actual), actual)
+	}
+
+	for day, durations := range expected {
+		for name, duration := range durations {
+			if actual[day][name] != duration {
+				t.Errorf("Expected %s on %s to last %d seconds, got %d", name, day, duration, actual[day][name])
+			}
+		}
+		if len(actual[day]) != len(durations) {
+			t.Errorf("Expected %d activities on %s, got %v", len(durations), day, actual[day])
+		}
+	}
+}
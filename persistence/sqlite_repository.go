@@ -14,58 +14,1062 @@ import (
 
 // SqliteRepository represents the connection to a SQLite database.
 type SqliteRepository struct {
-	db     *sql.DB
-	dbFile string
-	Clock  utils.Clock
+	db       *sql.DB
+	dbFile   string
+	location *time.Location
+	// concurrentTimers lets sessions of any activities run at the same time
+	concurrentTimers bool
+	Clock            utils.Clock
 }
 
 const DatabaseName = "gott.db"
 
+// queryer is implemented by both *sql.DB and *sql.Tx, so that queries can run inside or outside a transaction.
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
 // NewSqliteRepository creates a new SQLite repository struct
 func NewSqliteRepository() (*SqliteRepository, error) {
 
-	return &SqliteRepository{
-		Clock: utils.NewLiveClock(),
-	}, nil
+	return &SqliteRepository{
+		Clock:    utils.NewLiveClock(),
+		location: time.Local,
+	}, nil
+}
+
+// Initialize initializes the connection to the database and applies any pending schema migration, unless told to skip them.
+// Nothing is done while the application is not setup, since there is no place to store the database yet.
+func (repo *SqliteRepository) Initialize(config config.Config) error {
+	if config.Location != nil {
+		repo.location = config.Location
+	}
+	repo.concurrentTimers = config.ConcurrentTimers
+
+	if !config.AlreadySetup() {
+		return nil
+	}
+
+	dbFilePath := fmt.Sprintf("%s%s", config.UserDataLocation, DatabaseName)
+	// Transactions take the write lock when they begin, so that the checks done inside them are never stale.
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_txlock=immediate", dbFilePath))
+
+	if err != nil {
+		return err
+	}
+
+	repo.db = db
+	repo.dbFile = dbFilePath
+	if config.SkipMigrations {
+		return nil
+	}
+	return repo.Migrate()
+}
+
+// Shutdown shutsdown the database
+func (repo *SqliteRepository) Shutdown() error {
+	if repo.db == nil {
+		return nil
+	}
+	return repo.db.Close()
+}
+
+// Add adds a new activity to the database
+func (repo *SqliteRepository) Add(activity core.Activity) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		err := checkParentExists(tx, activity.Name)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(
+			"INSERT INTO activities (name, alias, description, concurrent) VALUES (?, ?, ?, ?)",
+			activity.Name,
+			activity.Alias,
+			activity.Description,
+			activity.Concurrent,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		activityId, err := res.LastInsertId()
+
+		if err != nil {
+			return err
+		}
+
+		return insertTags(tx, "activity_tags", "activity_id", activityId, activity.Tags)
+	})
+}
+
+// Delete deletes an activity from the database, along with its logs or moving them to another activity
+func (repo *SqliteRepository) Delete(activityNameOrAlias string, opts core.DeleteOptions) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activity, err := findActivity(tx, activityNameOrAlias)
+		if err != nil {
+			return err
+		}
+
+		var subActivities int
+		err = tx.QueryRow("SELECT COUNT(*) FROM activities WHERE substr(name, 1, ?) = ?", descendantsPrefixArgs(activity.Name)...).Scan(&subActivities)
+		if err != nil {
+			return err
+		}
+
+		if subActivities > 0 {
+			return fmt.Errorf("activity '%s' has sub-activities, please delete them first", activity.Name)
+		}
+
+		running, err := openActivityLogs(tx, repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		if core.LogOfActivity(running, activity.Id) != nil {
+			return fmt.Errorf("you are tracking the activity '%s', please stop it before deleting it", activity.Name)
+		}
+
+		var logs int
+		err = tx.QueryRow("SELECT COUNT(*) FROM activity_logs WHERE activity_id = ?", activity.Id).Scan(&logs)
+		if err != nil {
+			return err
+		}
+
+		err = opts.Check(*activity, logs > 0)
+		if err != nil {
+			return err
+		}
+
+		if opts.DeleteLogs {
+			err = deleteLogs(tx, "activity_id = ?", activity.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.MoveLogsTo != nil {
+			var targets int
+			err = tx.QueryRow("SELECT COUNT(*) FROM activities WHERE id = ?", opts.MoveLogsTo.Id).Scan(&targets)
+			if err != nil {
+				return err
+			}
+
+			if targets == 0 {
+				return errors.New("activity to move the logs to not found")
+			}
+
+			_, err = tx.Exec("UPDATE activity_logs SET activity_id = ? WHERE activity_id = ?", opts.MoveLogsTo.Id, activity.Id)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("DELETE FROM activity_tags WHERE activity_id = ?", activity.Id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("DELETE FROM activities WHERE id = ?", activity.Id)
+		return err
+	})
+}
+
+// List returns a list with all the activities in the database
+func (repo *SqliteRepository) List() ([]core.Activity, error) {
+	rows, err := repo.db.Query("SELECT id, name, COALESCE(alias, ''), COALESCE(description, ''), archived, concurrent FROM activities")
+
+	if err != nil {
+		return []core.Activity{}, err
+	}
+
+	defer rows.Close()
+
+	var activities []core.Activity
+	for rows.Next() {
+		var activityId int
+		var activityName string
+		var activityAlias string
+		var activityDesc string
+		var activityArchived bool
+		var activityConcurrent bool
+		err = rows.Scan(&activityId, &activityName, &activityAlias, &activityDesc, &activityArchived, &activityConcurrent)
+		if err != nil {
+			return []core.Activity{}, err
+		}
+
+		activities = append(activities, core.Activity{Id: activityId, Name: activityName, Alias: activityAlias, Description: activityDesc, Archived: activityArchived, Concurrent: activityConcurrent})
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return []core.Activity{}, err
+	}
+
+	return loadActivityTags(repo.db, activities)
+}
+
+// Find returns an activity
+func (repo *SqliteRepository) Find(activityNameOrAlias string) (*core.Activity, error) {
+	return findActivity(repo.db, activityNameOrAlias)
+}
+
+// Update updates an activity
+func (repo *SqliteRepository) Update(activityNameOrAlias string, updateOp core.UpdateActivity) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activity, err := findActivity(tx, activityNameOrAlias)
+		if err != nil {
+			return err
+		}
+
+		oldName := activity.Name
+		updateOp.Visit(activity)
+
+		if activity.Name != oldName {
+			renamed := core.Activity{Name: oldName}
+			err = renamed.ValidateRename(activity.Name)
+			if err != nil {
+				return err
+			}
+
+			if existing, _ := findActivity(tx, activity.Name); existing != nil {
+				return fmt.Errorf("an activity with name '%s' already exists", activity.Name)
+			}
+
+			err = checkParentExists(tx, activity.Name)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(
+				"UPDATE activities SET name = ? || substr(name, ?) WHERE substr(name, 1, ?) = ?",
+				append([]interface{}{activity.Name, len(oldName) + 1}, descendantsPrefixArgs(oldName)...)...,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.Exec(
+			"UPDATE activities SET name = ?, alias = ?, description = ?, concurrent = ? WHERE id = ?",
+			activity.Name,
+			activity.Alias,
+			activity.Description,
+			activity.Concurrent,
+			activity.Id,
+		)
+
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := res.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected != 1 {
+			panic("More than one activity updated! This shouldn't happen, please check your data")
+		}
+
+		return nil
+	})
+}
+
+// Archive archives an activity and its sub-activities
+func (repo *SqliteRepository) Archive(activityNameOrAlias string) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activity, err := findActivity(tx, activityNameOrAlias)
+		if err != nil {
+			return err
+		}
+
+		runningLogs, err := openActivityLogs(tx, repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		for _, running := range runningLogs {
+			if running.Activity.Id == activity.Id || running.Activity.IsDescendantOf(activity.Name) {
+				return fmt.Errorf("you are tracking the activity '%s', please stop it before archiving it", running.Activity.Name)
+			}
+		}
+
+		return setArchived(tx, activity, true)
+	})
+}
+
+// Unarchive restores an archived activity and its sub-activities
+func (repo *SqliteRepository) Unarchive(activityNameOrAlias string) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activity, err := findActivity(tx, activityNameOrAlias)
+		if err != nil {
+			return err
+		}
+
+		return setArchived(tx, activity, false)
+	})
+}
+
+// LogsForPeriod returns a list of activity logs for a given period.
+// Days are calendar days in the configured time zone, and sessions crossing midnight have their duration
+// apportioned to each day they span.
+func (repo *SqliteRepository) LogsForPeriod(period core.Period, filter core.LogFilter) (map[string][]core.ActivityDurationDayAggregation, error) {
+	from, to := periodBounds(period, repo.location)
+	activityLogs, err := queryActivityLogs(repo.db, inPeriodCondition, to, from, from)
+	if err != nil {
+		return nil, err
+	}
+
+	activityLogs = core.SettleOverdueLogs(activityLogs, repo.Clock.Now())
+	return core.AggregateLogsByDay(activityLogs, period, repo.location, filter), nil
+}
+
+// Start starts tracking the time for an activity
+func (repo *SqliteRepository) Start(activity core.Activity, opts core.StartOptions) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		runningLogs, err := openActivityLogs(tx, repo.Clock.Now())
+
+		if err != nil {
+			return err
+		}
+
+		for _, running := range runningLogs {
+			if !running.Activity.CanRunAlongside(activity, repo.concurrentTimers) {
+				return fmt.Errorf("you are already tracking the activity '%s', please stop that one before starting a new one", running.Activity.Name)
+			}
+		}
+
+		err = checkNotArchived(tx, activity)
+		if err != nil {
+			return err
+		}
+
+		now := repo.Clock.Now()
+		startedAt := opts.StartedAt(now)
+		err = repo.checkStart(tx, activity, startedAt, now)
+		if err == nil {
+			err = core.CheckPlannedEnd(startedAt, opts.Until)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.insertLog(tx, activity, startedAt, nil, opts)
+		return err
+	})
+}
+
+// LogSession records a session of an activity that was not tracked live
+func (repo *SqliteRepository) LogSession(activity core.Activity, interval core.Interval, opts core.StartOptions) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		err := checkNotArchived(tx, activity)
+		if err != nil {
+			return err
+		}
+
+		overlapping, err := repo.conflictingLogs(tx, activity, interval)
+		if err != nil {
+			return err
+		}
+
+		err = core.CheckSessionInterval(interval, overlapping, repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.insertLog(tx, activity, interval.Start, &interval.End, opts)
+		return err
+	})
+}
+
+// SessionsForPeriod returns the sessions, running or not, taking place in the days of the period, ordered by their start.
+// If an activity is given, only its sessions are returned.
+func (repo *SqliteRepository) SessionsForPeriod(period core.Period, activity *core.Activity) ([]core.ActivityLog, error) {
+	from, to := periodBounds(period, repo.location)
+	condition := inPeriodCondition
+	args := []interface{}{to, from, from}
+	if activity != nil {
+		condition += " AND activity_logs.activity_id = ?"
+		args = append(args, activity.Id)
+	}
+
+	activityLogs, err := queryActivityLogs(repo.db, condition, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.SettleOverdueLogs(activityLogs, repo.Clock.Now()), nil
+}
+
+// FindSession returns a session by its id
+func (repo *SqliteRepository) FindSession(logId int) (*core.ActivityLog, error) {
+	return findSession(repo.db, logId)
+}
+
+// UpdateSession changes the start, the stop or the activity of a session
+func (repo *SqliteRepository) UpdateSession(logId int, update core.SessionUpdate) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		session, err := findSession(tx, logId)
+		if err != nil {
+			return err
+		}
+
+		if update.Activity != nil {
+			err = checkNotArchived(tx, *update.Activity)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated := update.Apply(*session)
+		now := repo.Clock.Now()
+		end := now
+		if updated.StoppedAt != nil {
+			end = *updated.StoppedAt
+		}
+
+		overlapping, err := repo.conflictingLogs(tx, updated.Activity, core.Interval{Start: *updated.StartedAt, End: end})
+		if err != nil {
+			return err
+		}
+
+		err = core.CheckSession(updated, overlapping, now)
+		if err != nil {
+			return err
+		}
+
+		startTime, startOffset := storedTime(*updated.StartedAt)
+		var stopTime interface{}
+		var stopOffset interface{}
+		if updated.StoppedAt != nil {
+			stopTime, stopOffset = storedTime(*updated.StoppedAt)
+		}
+
+		_, err = tx.Exec(
+			"UPDATE activity_logs SET day = ?, started_at = ?, started_at_offset = ?, stopped_at = ?, stopped_at_offset = ?, activity_id = ? WHERE id = ?",
+			updated.StartedAt.In(repo.location).Format(utils.DateFormat),
+			startTime,
+			startOffset,
+			stopTime,
+			stopOffset,
+			updated.Activity.Id,
+			logId,
+		)
+		return err
+	})
+}
+
+// DeleteSession deletes a session
+func (repo *SqliteRepository) DeleteSession(logId int) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		_, err := findSession(tx, logId)
+		if err != nil {
+			return err
+		}
+
+		return deleteLogs(tx, "id = ?", logId)
+	})
+}
+
+// ApplySessionChanges inserts, updates and deletes sessions in a single transaction. No change is applied if any of them is not valid.
+func (repo *SqliteRepository) ApplySessionChanges(changes core.SessionChanges) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		for _, logId := range changes.Deletes {
+			_, err := findSession(tx, logId)
+			if err != nil {
+				return err
+			}
+
+			err = deleteLogs(tx, "id = ?", logId)
+			if err != nil {
+				return err
+			}
+		}
+
+		var changed []int
+		for _, update := range changes.Updates {
+			session, err := findSession(tx, update.Id)
+			if err != nil {
+				return err
+			}
+
+			if update.Activity.Id != session.Activity.Id {
+				err = checkNotArchived(tx, update.Activity)
+				if err != nil {
+					return err
+				}
+			}
+
+			startTime, startOffset := storedTime(*update.StartedAt)
+			var stopTime interface{}
+			var stopOffset interface{}
+			if update.StoppedAt != nil {
+				stopTime, stopOffset = storedTime(*update.StoppedAt)
+			}
+
+			_, err = tx.Exec(
+				"UPDATE activity_logs SET day = ?, started_at = ?, started_at_offset = ?, stopped_at = ?, stopped_at_offset = ?, activity_id = ?, note = ? WHERE id = ?",
+				update.StartedAt.In(repo.location).Format(utils.DateFormat),
+				startTime,
+				startOffset,
+				stopTime,
+				stopOffset,
+				update.Activity.Id,
+				update.Note,
+				update.Id,
+			)
+			if err != nil {
+				return err
+			}
+			changed = append(changed, update.Id)
+		}
+
+		for _, insert := range changes.Inserts {
+			err := checkNotArchived(tx, insert.Activity)
+			if err != nil {
+				return err
+			}
+
+			logId, err := repo.insertLog(tx, insert.Activity, *insert.StartedAt, insert.StoppedAt, core.StartOptions{Note: insert.Note, Tags: insert.Tags})
+			if err != nil {
+				return err
+			}
+			changed = append(changed, int(logId))
+		}
+
+		now := repo.Clock.Now()
+		for _, logId := range changed {
+			session, err := findSession(tx, logId)
+			if err != nil {
+				return err
+			}
+
+			end := now
+			if session.StoppedAt != nil {
+				end = *session.StoppedAt
+			}
+
+			overlapping, err := repo.conflictingLogs(tx, session.Activity, core.Interval{Start: *session.StartedAt, End: end})
+			if err != nil {
+				return err
+			}
+
+			err = core.CheckSession(*session, overlapping, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// insertLog records a log of the activity, not stopped yet if stoppedAt is nil, and returns its id
+func (repo *SqliteRepository) insertLog(q queryer, activity core.Activity, startedAt time.Time, stoppedAt *time.Time, opts core.StartOptions) (int64, error) {
+	startTime, startOffset := storedTime(startedAt)
+	var stopTime interface{}
+	var stopOffset interface{}
+	if stoppedAt != nil {
+		stopTime, stopOffset = storedTime(*stoppedAt)
+	}
+	var plannedEndTime interface{}
+	var plannedEndOffset interface{}
+	if opts.Until != nil {
+		plannedEndTime, plannedEndOffset = storedTime(*opts.Until)
+	}
+
+	res, err := q.Exec(
+		"INSERT INTO activity_logs (day, started_at, started_at_offset, stopped_at, stopped_at_offset, activity_id, note, planned_end, planned_end_offset) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		startedAt.In(repo.location).Format(utils.DateFormat),
+		startTime,
+		startOffset,
+		stopTime,
+		stopOffset,
+		activity.Id,
+		core.AppendNote("", opts.Note),
+		plannedEndTime,
+		plannedEndOffset,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	logId, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return logId, insertTags(q, "activity_log_tags", "activity_log_id", logId, opts.Tags)
+}
+
+// WipeLogsPeriodAndActivity deletes the logs of a given activity started in a given period
+func (repo *SqliteRepository) WipeLogsPeriodAndActivity(period core.Period, activity *core.Activity) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		from, to := periodBounds(period, repo.location)
+		return deleteLogs(tx, "activity_id = ? AND started_at >= ? AND started_at < ?", activity.Id, from, to)
+	})
+}
+
+// WipeLogsPeriod deletes the logs started in a given period
+func (repo *SqliteRepository) WipeLogsPeriod(period core.Period) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		from, to := periodBounds(period, repo.location)
+		return deleteLogs(tx, "started_at >= ? AND started_at < ?", from, to)
+	})
+}
+
+// CurrentlyTrackedActivity returns the activity beeing currently tracked, the one started last if there are several, if any
+func (repo *SqliteRepository) CurrentlyTrackedActivity() (*core.Activity, error) {
+	session, err := repo.CurrentSession()
+	if err != nil || session == nil {
+		return nil, err
+	}
+
+	return &session.Activity, nil
+}
+
+// CurrentSession returns the log of the activity beeing currently tracked, the one started last if there are several, if any
+func (repo *SqliteRepository) CurrentSession() (*core.ActivityLog, error) {
+	activityLogs, err := repo.CurrentSessions()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activityLogs) == 0 {
+		return nil, nil
+	}
+
+	return &activityLogs[len(activityLogs)-1], nil
+}
+
+// CurrentSessions returns the logs of all the activities beeing currently tracked, ordered by their start
+func (repo *SqliteRepository) CurrentSessions() ([]core.ActivityLog, error) {
+	activityLogs, err := queryActivityLogs(repo.db, "activity_logs.stopped_at IS NULL")
+	if err != nil {
+		return nil, err
+	}
+
+	var running []core.ActivityLog
+	for _, log := range activityLogs {
+		if !log.IsOverdue(repo.Clock.Now()) {
+			running = append(running, log)
+		}
+	}
+	return running, nil
+}
+
+// CurrentActivityLogs returns the running logs, along with the logs of their activities stopped since the instant.
+// They are read in a single query, with their pauses but without their tags, and nothing is written, so that logs
+// running past their planned end are returned as running.
+func (repo *SqliteRepository) CurrentActivityLogs(since time.Time) ([]core.ActivityLog, error) {
+	sinceTime, _ := storedTime(since)
+	rows, err := repo.db.Query(`
+		SELECT activity_logs.id,
+			   activity_logs.started_at,
+			   activity_logs.started_at_offset,
+			   activity_logs.stopped_at,
+			   activity_logs.stopped_at_offset,
+			   activity_logs.planned_end,
+			   activity_logs.planned_end_offset,
+			   activities.id,
+			   activities.name,
+			   COALESCE(activities.alias, ''),
+			   COALESCE(activities.description, ''),
+			   activities.concurrent,
+			   activity_log_pauses.id,
+			   activity_log_pauses.paused_at,
+			   activity_log_pauses.paused_at_offset,
+			   activity_log_pauses.resumed_at,
+			   activity_log_pauses.resumed_at_offset
+		FROM activity_logs
+		JOIN activities ON activities.id = activity_logs.activity_id
+		LEFT JOIN activity_log_pauses ON activity_log_pauses.activity_log_id = activity_logs.id
+		WHERE activity_logs.activity_id IN (SELECT activity_id FROM activity_logs WHERE stopped_at IS NULL)
+			AND (activity_logs.stopped_at IS NULL OR activity_logs.stopped_at >= ?)
+		ORDER BY activity_logs.started_at, activity_logs.id, activity_log_pauses.paused_at
+	`, sinceTime)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var activityLogs []core.ActivityLog
+	for rows.Next() {
+		var logId int
+		var logStartedAt *time.Time
+		var logStartedAtOffset int
+		var logStoppedAt *time.Time
+		var logStoppedAtOffset sql.NullInt64
+		var logPlannedEnd *time.Time
+		var logPlannedEndOffset sql.NullInt64
+		var activity core.Activity
+		var pauseId sql.NullInt64
+		var pausedAt *time.Time
+		var pausedAtOffset sql.NullInt64
+		var resumedAt *time.Time
+		var resumedAtOffset sql.NullInt64
+		err = rows.Scan(
+			&logId,
+			&logStartedAt,
+			&logStartedAtOffset,
+			&logStoppedAt,
+			&logStoppedAtOffset,
+			&logPlannedEnd,
+			&logPlannedEndOffset,
+			&activity.Id,
+			&activity.Name,
+			&activity.Alias,
+			&activity.Description,
+			&activity.Concurrent,
+			&pauseId,
+			&pausedAt,
+			&pausedAtOffset,
+			&resumedAt,
+			&resumedAtOffset,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// A log with several pauses comes in several rows, one per pause
+		if len(activityLogs) == 0 || activityLogs[len(activityLogs)-1].Id != logId {
+			activityLogs = append(activityLogs, core.ActivityLog{
+				Id:         logId,
+				StartedAt:  loadedTime(logStartedAt, logStartedAtOffset),
+				StoppedAt:  loadedTime(logStoppedAt, int(logStoppedAtOffset.Int64)),
+				PlannedEnd: loadedTime(logPlannedEnd, int(logPlannedEndOffset.Int64)),
+				Activity:   activity,
+			})
+		}
+
+		if pauseId.Valid {
+			log := &activityLogs[len(activityLogs)-1]
+			log.Pauses = append(log.Pauses, core.Pause{
+				Id:        int(pauseId.Int64),
+				PausedAt:  loadedTime(pausedAt, int(pausedAtOffset.Int64)),
+				ResumedAt: loadedTime(resumedAt, int(resumedAtOffset.Int64)),
+			})
+		}
+	}
+
+	return activityLogs, rows.Err()
+}
+
+// LastSession returns the log of the activity tracked most recently, running or not, if any
+func (repo *SqliteRepository) LastSession() (*core.ActivityLog, error) {
+	activityLogs, err := queryActivityLogs(
+		repo.db,
+		"activity_logs.id = (SELECT id FROM activity_logs ORDER BY started_at DESC, id DESC LIMIT 1)",
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activityLogs) == 0 {
+		return nil, nil
+	}
+
+	activityLogs = core.SettleOverdueLogs(activityLogs, repo.Clock.Now())
+	return &activityLogs[0], nil
+}
+
+// SetNote replaces the note of an activity log
+func (repo *SqliteRepository) SetNote(logId int, note string) error {
+	res, err := repo.db.Exec("UPDATE activity_logs SET note = ? WHERE id = ?", core.AppendNote("", note), logId)
+
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("activity log %d not found", logId)
+	}
+
+	return nil
+}
+
+// TagActivity adds tags to an activity
+func (repo *SqliteRepository) TagActivity(activity core.Activity, tags []string) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		return insertTags(tx, "activity_tags", "activity_id", int64(activity.Id), tags)
+	})
+}
+
+// UntagActivity removes tags from an activity
+func (repo *SqliteRepository) UntagActivity(activity core.Activity, tags []string) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		for _, tag := range tags {
+			_, err := tx.Exec(
+				"DELETE FROM activity_tags WHERE activity_id = ? AND tag_id IN (SELECT id FROM tags WHERE name = ?)",
+				activity.Id,
+				tag,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Tags returns all the tags in use, by activities or sessions, sorted
+func (repo *SqliteRepository) Tags() ([]string, error) {
+	rows, err := repo.db.Query(`
+		SELECT name FROM tags
+		WHERE id IN (SELECT tag_id FROM activity_tags UNION SELECT tag_id FROM activity_log_tags)
+		ORDER BY name
+	`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		err = rows.Scan(&tag)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// RenameTag renames a tag in every activity and session that has it. Renaming to an existing tag merges both.
+func (repo *SqliteRepository) RenameTag(oldName string, newName string) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		var oldId int64
+		err := tx.QueryRow("SELECT id FROM tags WHERE name = ?", oldName).Scan(&oldId)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("tag '%s' not found", oldName)
+		}
+		if err != nil {
+			return err
+		}
+
+		newId, err := tagId(tx, newName)
+		if err != nil {
+			return err
+		}
+
+		if newId == oldId {
+			return nil
+		}
+
+		for _, table := range []struct{ name, column string }{{"activity_tags", "activity_id"}, {"activity_log_tags", "activity_log_id"}} {
+			_, err = tx.Exec(
+				fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, tag_id) SELECT %s, ? FROM %s WHERE tag_id = ?", table.name, table.column, table.column, table.name),
+				newId,
+				oldId,
+			)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ?", table.name), oldId)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec("DELETE FROM tags WHERE id = ?", oldId)
+		return err
+	})
+}
+
+// Pause starts a break in the activity beeing currently tracked
+func (repo *SqliteRepository) Pause() error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activityLogs, err := openActivityLogs(tx, repo.Clock.Now())
+
+		if err != nil {
+			return err
+		}
+
+		err = core.CheckPause(activityLogs)
+		if err != nil {
+			return err
+		}
+
+		pauseTime, pauseOffset := storedTime(repo.Clock.Now())
+		for _, log := range activityLogs {
+			if log.IsPaused() {
+				continue
+			}
+
+			_, err = tx.Exec(
+				"INSERT INTO activity_log_pauses (activity_log_id, paused_at, paused_at_offset) VALUES (?, ?, ?)",
+				log.Id,
+				pauseTime,
+				pauseOffset,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Resume ends the break in the activity beeing currently tracked
+func (repo *SqliteRepository) Resume() error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activityLogs, err := openActivityLogs(tx, repo.Clock.Now())
+
+		if err != nil {
+			return err
+		}
+
+		err = core.CheckResume(activityLogs)
+		if err != nil {
+			return err
+		}
+
+		for _, log := range activityLogs {
+			if log.IsPaused() {
+				err = resumePause(tx, log.OpenPause(), repo.Clock.Now())
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
 }
 
-// Initialize initializes the connection to the database
-func (repo *SqliteRepository) Initialize(config config.Config) error {
-	dbFilePath := fmt.Sprintf("%s%s", config.UserDataLocation, DatabaseName)
-	db, err := sql.Open("sqlite3", dbFilePath)
+// Stop stops tracking the time for an activity
+func (repo *SqliteRepository) Stop(activity core.Activity, opts core.StopOptions) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activityLogs, err := openActivityLogs(tx, repo.Clock.Now())
 
-	if err != nil {
-		return nil
-	}
+		if err != nil {
+			return err
+		}
 
-	repo.db = db
-	repo.dbFile = dbFilePath
-	return nil
-}
+		if len(activityLogs) == 0 {
+			return errors.New("you are not tracking any activity, please start tracking one with the 'start' command")
+		}
 
-// Shutdown shutsdown the database
-func (repo *SqliteRepository) Shutdown() error {
-	return repo.db.Close()
+		logStartedAndNotStopped := core.LogOfActivity(activityLogs, activity.Id)
+
+		if logStartedAndNotStopped == nil {
+			return fmt.Errorf("you are not tracking the activity '%s'", activity.Name)
+		}
+
+		now := repo.Clock.Now()
+		stoppedAt := opts.StoppedAt(now)
+		err = core.CheckStop(*logStartedAndNotStopped, stoppedAt, now)
+		if err != nil {
+			return err
+		}
+
+		return stopLog(tx, *logStartedAndNotStopped, stoppedAt, opts)
+	})
 }
 
-// Add adds a new activity to the database
-func (repo *SqliteRepository) Add(activity core.Activity) error {
-	sql := fmt.Sprintf("INSERT INTO activities (name, alias, description) VALUES ('%s', '%s', '%s')", activity.Name, activity.Alias, activity.Description)
+// Switch stops the sessions being tracked of activities that are not concurrent, if any, and starts tracking the time
+// for another activity at the same instant
+func (repo *SqliteRepository) Switch(activity core.Activity, opts core.StartOptions) error {
+	return repo.inTransaction(func(tx *sql.Tx) error {
+		activityLogs, err := openActivityLogs(tx, repo.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		if running := core.LogOfActivity(activityLogs, activity.Id); running != nil {
+			return fmt.Errorf("you are already tracking the activity '%s'", running.Activity.Name)
+		}
+
+		err = checkNotArchived(tx, activity)
+		if err != nil {
+			return err
+		}
+
+		now := repo.Clock.Now()
+		switchedAt := opts.StartedAt(now)
+		for _, running := range activityLogs {
+			if running.Activity.Concurrent {
+				continue
+			}
+
+			err = core.CheckStop(running, switchedAt, now)
+			if err != nil {
+				return err
+			}
+
+			err = stopLog(tx, running, switchedAt, core.StopOptions{})
+			if err != nil {
+				return err
+			}
+		}
 
-	_, err := repo.db.Exec(sql)
+		err = repo.checkStart(tx, activity, switchedAt, now)
+		if err == nil {
+			err = core.CheckPlannedEnd(switchedAt, opts.Until)
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.insertLog(tx, activity, switchedAt, nil, opts)
+		return err
+	})
+}
+
+// checkStart returns an error if a session of the activity cannot start at the instant, given the sessions already recorded
+func (repo *SqliteRepository) checkStart(q queryer, activity core.Activity, instant time.Time, now time.Time) error {
+	if instant.After(now) {
+		return core.CheckStart(instant, nil, now)
+	}
 
+	overlapping, err := repo.conflictingLogs(q, activity, core.Interval{Start: instant, End: now})
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return core.CheckStart(instant, overlapping, now)
 }
 
-// Delete deletes an activity from the database
-func (repo *SqliteRepository) Delete(activityNameOrAlias string) error {
-	sql := fmt.Sprintf("DELETE FROM activities WHERE name = '%s' OR alias = '%s'", activityNameOrAlias, activityNameOrAlias)
+// stopLog stops the running log at the given instant, resuming it first if it is paused
+func stopLog(q queryer, log core.ActivityLog, now time.Time, opts core.StopOptions) error {
+	if log.IsPaused() {
+		err := resumePause(q, log.OpenPause(), now)
+		if err != nil {
+			return err
+		}
+	}
 
-	res, err := repo.db.Exec(sql)
+	stopTime, stopOffset := storedTime(now)
+	res, err := q.Exec(
+		"UPDATE activity_logs SET stopped_at = ?, stopped_at_offset = ?, note = ? WHERE id = ?",
+		stopTime,
+		stopOffset,
+		core.AppendNote(log.Note, opts.Note),
+		log.Id,
+	)
 
 	if err != nil {
 		return err
@@ -77,50 +1081,42 @@ func (repo *SqliteRepository) Delete(activityNameOrAlias string) error {
 		return err
 	}
 
-	if rowsAffected == 0 {
-		return errors.New("activity not found")
+	if rowsAffected != 1 {
+		panic("More than one activity log updated! This shouldn't happen, please check your data")
 	}
 
 	return nil
 }
 
-// List returns a list with all the activities in the database
-func (repo *SqliteRepository) List() ([]core.Activity, error) {
-	rows, err := repo.db.Query("SELECT name, alias, description FROM activities")
-
+// inTransaction runs fn inside a transaction, which is committed if fn succeeds and rolled back otherwise.
+func (repo *SqliteRepository) inTransaction(fn func(*sql.Tx) error) (err error) {
+	tx, err := repo.db.Begin()
 	if err != nil {
-		return []core.Activity{}, err
+		return err
 	}
 
-	defer rows.Close()
-
-	var activities []core.Activity
-	for rows.Next() {
-		var activityId int
-		var activityName string
-		var activityAlias string
-		var activityDesc string
-		err = rows.Scan(&activityName, &activityAlias, &activityDesc)
-		if err != nil {
-			return []core.Activity{}, err
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
 		}
+	}()
 
-		activities = append(activities, core.Activity{Id: activityId, Name: activityName, Alias: activityAlias, Description: activityDesc})
-	}
-
-	err = rows.Err()
-
+	err = fn(tx)
 	if err != nil {
-		return []core.Activity{}, err
+		tx.Rollback()
+		return err
 	}
 
-	return activities, nil
+	return tx.Commit()
 }
 
-// Find returns an activity
-func (repo *SqliteRepository) Find(activityNameOrAlias string) (*core.Activity, error) {
-	query := fmt.Sprintf("SELECT id, name, alias, description FROM activities WHERE name = '%s' OR alias = '%s'", activityNameOrAlias, activityNameOrAlias)
-	rows, err := repo.db.Query(query)
+func findActivity(q queryer, activityNameOrAlias string) (*core.Activity, error) {
+	rows, err := q.Query(
+		"SELECT id, name, COALESCE(alias, ''), COALESCE(description, ''), archived, concurrent FROM activities WHERE name = ? OR alias = ?",
+		activityNameOrAlias,
+		activityNameOrAlias,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -133,12 +1129,14 @@ func (repo *SqliteRepository) Find(activityNameOrAlias string) (*core.Activity,
 		var activityName string
 		var activityAlias string
 		var activityDesc string
-		err = rows.Scan(&activityId, &activityName, &activityAlias, &activityDesc)
+		var activityArchived bool
+		var activityConcurrent bool
+		err = rows.Scan(&activityId, &activityName, &activityAlias, &activityDesc, &activityArchived, &activityConcurrent)
 		if err != nil {
 			return nil, err
 		}
 
-		activity = &core.Activity{Id: activityId, Name: activityName, Alias: activityAlias, Description: activityDesc}
+		activity = &core.Activity{Id: activityId, Name: activityName, Alias: activityAlias, Description: activityDesc, Archived: activityArchived, Concurrent: activityConcurrent}
 	}
 
 	err = rows.Err()
@@ -151,271 +1149,266 @@ func (repo *SqliteRepository) Find(activityNameOrAlias string) (*core.Activity,
 		return nil, errors.New("activity not found")
 	}
 
-	return activity, nil
-}
-
-// Update updates an activity
-func (repo *SqliteRepository) Update(activityNameOrAlias string, updateOp core.UpdateActivity) error {
-	activity, err := repo.Find(activityNameOrAlias)
+	activities, err := loadActivityTags(q, []core.Activity{*activity})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	updateOp.Visit(activity)
-
-	updateQuery := fmt.Sprintf("UPDATE activities SET name = '%s', alias = '%s', description = '%s' WHERE id = %v", activity.Name, activity.Alias, activity.Description, activity.Id)
-	res, err := repo.db.Exec(updateQuery)
+	return &activities[0], nil
+}
 
-	if err != nil {
-		return err
-	}
+// setArchived archives, or restores, the activity and its sub-activities
+func setArchived(q queryer, activity *core.Activity, archived bool) error {
+	_, err := q.Exec(
+		"UPDATE activities SET archived = ? WHERE id = ? OR substr(name, 1, ?) = ?",
+		append([]interface{}{archived, activity.Id}, descendantsPrefixArgs(activity.Name)...)...,
+	)
+	return err
+}
 
-	rowsAffected, err := res.RowsAffected()
+// overlappingLogs returns the logs, running or not, sharing some time with the interval
+func overlappingLogs(q queryer, interval core.Interval) ([]core.ActivityLog, error) {
+	from, _ := storedTime(interval.Start)
+	to, _ := storedTime(interval.End)
+	return queryActivityLogs(
+		q,
+		"activity_logs.started_at < ? AND (activity_logs.stopped_at IS NULL OR activity_logs.stopped_at > ?)",
+		to,
+		from,
+	)
+}
 
+// conflictingLogs returns the logs, running or not, sharing some time with the interval, that cannot overlap
+// a session of the activity
+func (repo *SqliteRepository) conflictingLogs(q queryer, activity core.Activity, interval core.Interval) ([]core.ActivityLog, error) {
+	overlapping, err := overlappingLogs(q, interval)
 	if err != nil {
-		return err
-	}
-
-	if rowsAffected != 1 {
-		panic("More than one activity updated! This shouldn't happen, please check your data")
+		return nil, err
 	}
-
-	return nil
+	return core.ConflictingLogs(activity, overlapping, repo.concurrentTimers), nil
 }
 
-// LogsForPeriod returns a list of activity logs for a given period
-func (repo *SqliteRepository) LogsForPeriod(period core.Period) (map[string][]core.ActivityDurationDayAggregation, error) {
-	stmt := `
-		SELECT activities.id,
-			   activities.name,
-			   activities.alias,
-			   activities.description,
-			   agg.day,
-			   agg.duration_in_seconds
-		FROM (
-			SELECT
-				activity_id,
-				day,
-				SUM(CAST((JulianDay(stopped_at) - JulianDay(started_at)) * 24 * 60 * 60 AS integer)) AS duration_in_seconds
-			FROM
-				activity_logs
-			WHERE
-				day BETWEEN '%s' AND '%s'
-			GROUP BY activity_id, day
-		) AS agg, activities
-		WHERE activities.id = agg.activity_id;
-	`
-
-	query := fmt.Sprintf(stmt, period.StartDateDay(), period.EndDateDay())
-	rows, err := repo.db.Query(query)
+// findSession returns the session with the given id
+func findSession(q queryer, logId int) (*core.ActivityLog, error) {
+	sessions, err := queryActivityLogs(q, "activity_logs.id = ?", logId)
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
-
-	result := make(map[string][]core.ActivityDurationDayAggregation)
-
-	for rows.Next() {
-		var activityId int
-		var activityName string
-		var activityAlias string
-		var activityDescription string
-		var day time.Time
-		var durationInSeconds int
-
-		err = rows.Scan(&activityId, &activityName, &activityAlias, &activityDescription, &day, &durationInSeconds)
-		if err != nil {
-			return nil, err
-		}
-
-		date := day.Format(utils.DateFormat)
-
-		if err != nil {
-			return nil, err
-		}
-
-		activity := core.Activity{Id: activityId, Name: activityName, Alias: activityAlias, Description: activityDescription}
-		result[date] = append(result[date], core.ActivityDurationDayAggregation{Activity: activity, Date: date, Duration: durationInSeconds})
-	}
-
-	err = rows.Err()
-
-	if err != nil {
-		return nil, err
+	if len(sessions) == 0 {
+		return nil, fmt.Errorf("session %d not found", logId)
 	}
 
-	return result, nil
+	return &sessions[0], nil
 }
 
-// Start starts tracking the time for an activity
-func (repo *SqliteRepository) Start(activity core.Activity) error {
-	activityStartedAndNotStopped, err := repo.CurrentlyTrackedActivity()
-
+// checkNotArchived returns an error if the activity is archived, since archived activities cannot be tracked
+func checkNotArchived(q queryer, activity core.Activity) error {
+	var archived bool
+	err := q.QueryRow("SELECT archived FROM activities WHERE id = ?", activity.Id).Scan(&archived)
+	if err == sql.ErrNoRows {
+		return errors.New("activity not found")
+	}
 	if err != nil {
 		return err
 	}
 
-	if activityStartedAndNotStopped != nil {
-		return fmt.Errorf("you are already tracking the activity '%s', please stop that one before starting a new one", activityStartedAndNotStopped.Name)
+	if archived {
+		return fmt.Errorf("activity '%s' is archived, please unarchive it before tracking it", activity.Name)
 	}
 
-	startTime := utils.TimeToStandardDateTimeFormat(repo.Clock.Now())
-	sql := fmt.Sprintf("INSERT INTO activity_logs (day, started_at, activity_id) VALUES (DATE(), '%v', '%v')", startTime, activity.Id)
-	_, err = repo.db.Exec(sql)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 
-// WipeLogsPeriodAndActivity deletes logs for a given activity and for a given period
-func (repo *SqliteRepository) WipeLogsPeriodAndActivity(period core.Period, activity *core.Activity) error {
-	sql := `
-		DELETE
-		FROM activity_logs
-		WHERE activity_id = %v AND day BETWEEN '%s' AND '%s';
-	`
-
-	query := fmt.Sprintf(sql, activity.Id, period.StartDateDay(), period.EndDateDay())
-	res, err := repo.db.Exec(query)
+// checkParentExists returns an error if the activity with the given name is a sub-activity of an unknown activity
+func checkParentExists(q queryer, name string) error {
+	parentName := core.ParentActivityName(name)
+	if parentName == "" {
+		return nil
+	}
 
+	var parents int
+	err := q.QueryRow("SELECT COUNT(*) FROM activities WHERE name = ?", parentName).Scan(&parents)
 	if err != nil {
 		return err
 	}
 
-	_, errWipe := res.RowsAffected()
-
-	if errWipe != nil {
-		return errWipe
+	if parents == 0 {
+		return fmt.Errorf("parent activity '%s' does not exist, please add it first", parentName)
 	}
 
 	return nil
 }
 
-// WipeLogsPeriodAndActivity deletes logs for a given period
-func (repo *SqliteRepository) WipeLogsPeriod(period core.Period) error {
-	sql := `
-		DELETE
-		FROM activity_logs
-		WHERE day BETWEEN '%s' AND '%s';
-	`
-
-	query := fmt.Sprintf(sql, period.StartDateDay(), period.EndDateDay())
-	res, err := repo.db.Exec(query)
+// descendantsPrefixArgs returns the arguments of the condition "substr(name, 1, ?) = ?", which matches the
+// sub-activities, at any level, of the activity with the given name
+func descendantsPrefixArgs(name string) []interface{} {
+	prefix := name + core.ActivitySeparator
+	return []interface{}{len(prefix), prefix}
+}
 
+// tagId returns the id of the tag with the given name, creating it if needed
+func tagId(q queryer, name string) (int64, error) {
+	_, err := q.Exec("INSERT OR IGNORE INTO tags (name) VALUES (?)", name)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, errWipe := res.RowsAffected()
+	var id int64
+	err = q.QueryRow("SELECT id FROM tags WHERE name = ?", name).Scan(&id)
+	return id, err
+}
 
-	if errWipe != nil {
-		return errWipe
-	}
+// insertTags links the tags, created if needed, to the row with the given id, through the given link table
+func insertTags(q queryer, table string, column string, id int64, tags []string) error {
+	for _, tag := range tags {
+		tagId, err := tagId(q, tag)
+		if err != nil {
+			return err
+		}
 
+		_, err = q.Exec(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, tag_id) VALUES (?, ?)", table, column), id, tagId)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-// CurrentlyTrackedActivity returns the activity beeing currently tracked, if any
-func (repo *SqliteRepository) CurrentlyTrackedActivity() (*core.Activity, error) {
-	activityLogs, err := repo.activityLogStartedAt(repo.Clock.Now())
+// queryTags returns the names of the tags, sorted, per id of the rows they are linked to. The query must select the id and the tag name.
+func queryTags(q queryer, query string, args ...interface{}) (map[int][]string, error) {
+	rows, err := q.Query(query, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	var activityStartedAndNotStopped *core.Activity
+	defer rows.Close()
 
-	for i := range activityLogs {
-		if activityLogs[i].StartedAt != nil && activityLogs[i].StoppedAt == nil {
-			activityStartedAndNotStopped = &activityLogs[i].Activity
-			break
+	tags := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var tag string
+		err = rows.Scan(&id, &tag)
+		if err != nil {
+			return nil, err
 		}
+		tags[id] = append(tags[id], tag)
 	}
 
-	if activityStartedAndNotStopped != nil {
-		return activityStartedAndNotStopped, nil
-	}
+	return tags, rows.Err()
+}
 
-	return nil, nil
+// activityTagsById returns the names of the tags, sorted, of every activity with tags
+func activityTagsById(q queryer) (map[int][]string, error) {
+	return queryTags(q, `
+		SELECT activity_tags.activity_id, tags.name
+		FROM activity_tags, tags
+		WHERE tags.id = activity_tags.tag_id
+		ORDER BY tags.name
+	`)
 }
 
-// Stop stops tracking the time for an activity
-func (repo *SqliteRepository) Stop(activity core.Activity) error {
-	activityLogs, err := repo.activityLogStartedAt(repo.Clock.Now())
+// loadActivityTags fills the tags of the activities
+func loadActivityTags(q queryer, activities []core.Activity) ([]core.Activity, error) {
+	if len(activities) == 0 {
+		return activities, nil
+	}
+
+	tags, err := activityTagsById(q)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(activityLogs) == 0 {
-		return errors.New("you are not tracking any activity today, please start tracking one with the 'start' command")
+	for i := range activities {
+		activities[i].Tags = tags[activities[i].Id]
 	}
 
-	var activityStartedAndNotStopped *core.Activity
-
-	for i := range activityLogs {
-		if activityLogs[i].StartedAt != nil && activityLogs[i].StoppedAt == nil {
-			activityStartedAndNotStopped = &activityLogs[i].Activity
-			break
-		}
-	}
+	return activities, nil
+}
 
-	if activityStartedAndNotStopped != nil && activityStartedAndNotStopped.Id != activity.Id {
-		return fmt.Errorf("you are already tracking the activity '%s', please stop that one before starting a new one", activityStartedAndNotStopped.Name)
-	}
+// resumePause ends the pause at the given instant
+func resumePause(q queryer, pause *core.Pause, instant time.Time) error {
+	resumeTime, resumeOffset := storedTime(instant)
+	_, err := q.Exec(
+		"UPDATE activity_log_pauses SET resumed_at = ?, resumed_at_offset = ? WHERE id = ?",
+		resumeTime,
+		resumeOffset,
+		pause.Id,
+	)
+	return err
+}
 
-	var logIncompleteToday *core.ActivityLog
-	for i := range activityLogs {
-		if activityLogs[i].Activity.Id == activity.Id && (activityLogs[i].StartedAt != nil && activityLogs[i].StoppedAt == nil) {
-			logIncompleteToday = &activityLogs[i]
-			break
-		}
+// deleteLogs deletes the logs matching the condition, along with their pauses and tags
+func deleteLogs(q queryer, condition string, args ...interface{}) error {
+	_, err := q.Exec("DELETE FROM activity_log_tags WHERE activity_log_id IN (SELECT id FROM activity_logs WHERE "+condition+")", args...)
+	if err != nil {
+		return err
 	}
 
-	if logIncompleteToday == nil {
-		return fmt.Errorf("you are not tracking this activity. Please start tracking it with `tt start %s`", activity.Name)
+	_, err = q.Exec("DELETE FROM activity_log_pauses WHERE activity_log_id IN (SELECT id FROM activity_logs WHERE "+condition+")", args...)
+	if err != nil {
+		return err
 	}
 
-	stopTime := utils.TimeToStandardDateTimeFormat(repo.Clock.Now())
-	updateQuery := fmt.Sprintf("UPDATE activity_logs SET stopped_at = '%v' WHERE id = %v", stopTime, logIncompleteToday.Id)
-	res, err := repo.db.Exec(updateQuery)
+	_, err = q.Exec("DELETE FROM activity_logs WHERE "+condition, args...)
+	return err
+}
 
+// openActivityLogs returns the logs that were started and not stopped yet, regardless of the day they started.
+// The logs running past their planned end are stopped at that end first.
+func openActivityLogs(q queryer, now time.Time) ([]core.ActivityLog, error) {
+	err := stopOverdueLogs(q, now)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	return queryActivityLogs(q, "activity_logs.stopped_at IS NULL")
+}
 
+// stopOverdueLogs stops the logs running past their planned end at that end
+func stopOverdueLogs(q queryer, now time.Time) error {
+	nowTime, _ := storedTime(now)
+	activityLogs, err := queryActivityLogs(q, "activity_logs.stopped_at IS NULL AND activity_logs.planned_end <= ?", nowTime)
 	if err != nil {
 		return err
 	}
 
-	if rowsAffected != 1 {
-		panic("More than one activity log updated! This shouldn't happen, please check your data")
+	for _, log := range activityLogs {
+		err = stopLog(q, log, *log.PlannedEnd, core.StopOptions{})
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (repo *SqliteRepository) activityLogStartedAt(instant time.Time) ([]core.ActivityLog, error) {
-	date := instant.Format(utils.DateFormat)
-
+// queryActivityLogs returns the logs, with their activity, matching the given condition, ordered by their start.
+func queryActivityLogs(q queryer, condition string, args ...interface{}) ([]core.ActivityLog, error) {
 	query := `
 		SELECT activity_logs.id,
 			   activity_logs.day,
 			   activity_logs.started_at,
+			   activity_logs.started_at_offset,
 			   activity_logs.stopped_at,
+			   activity_logs.stopped_at_offset,
+			   activity_logs.note,
+			   activity_logs.planned_end,
+			   activity_logs.planned_end_offset,
 			   activities.id,
 			   activities.name,
-			   activities.alias,
-			   activities.description
+			   COALESCE(activities.alias, ''),
+			   COALESCE(activities.description, ''),
+			   activities.archived,
+			   activities.concurrent
 		FROM activity_logs, activities
-		WHERE day = DATE('%s') AND activities.id = activity_logs.activity_id
+		WHERE activities.id = activity_logs.activity_id AND ` + condition + `
+		ORDER BY activity_logs.started_at
 	`
 
-	rows, err := repo.db.Query(fmt.Sprintf(query, date))
+	rows, err := q.Query(query, args...)
 
 	if err != nil {
 		return nil, err
@@ -426,37 +1419,55 @@ func (repo *SqliteRepository) activityLogStartedAt(instant time.Time) ([]core.Ac
 	var activityLogs []core.ActivityLog
 	for rows.Next() {
 		var logId int
-		var logDay string
+		var logDay time.Time
 		var logStartedAt *time.Time
+		var logStartedAtOffset int
 		var logStoppedAt *time.Time
+		var logStoppedAtOffset sql.NullInt64
+		var logNote string
+		var logPlannedEnd *time.Time
+		var logPlannedEndOffset sql.NullInt64
 		var activityId int
 		var activityName string
 		var activityAlias string
 		var activityDesc string
+		var activityArchived bool
+		var activityConcurrent bool
 		err = rows.Scan(
 			&logId,
 			&logDay,
 			&logStartedAt,
+			&logStartedAtOffset,
 			&logStoppedAt,
+			&logStoppedAtOffset,
+			&logNote,
+			&logPlannedEnd,
+			&logPlannedEndOffset,
 			&activityId,
 			&activityName,
 			&activityAlias,
 			&activityDesc,
+			&activityArchived,
+			&activityConcurrent,
 		)
 		if err != nil {
 			return nil, err
 		}
 
 		activityLog := core.ActivityLog{
-			Id:        logId,
-			Date:      logDay,
-			StartedAt: logStartedAt,
-			StoppedAt: logStoppedAt,
+			Id:         logId,
+			Date:       logDay.Format(utils.DateFormat),
+			StartedAt:  loadedTime(logStartedAt, logStartedAtOffset),
+			StoppedAt:  loadedTime(logStoppedAt, int(logStoppedAtOffset.Int64)),
+			Note:       logNote,
+			PlannedEnd: loadedTime(logPlannedEnd, int(logPlannedEndOffset.Int64)),
 			Activity: core.Activity{
 				Id:          activityId,
 				Name:        activityName,
 				Alias:       activityAlias,
 				Description: activityDesc,
+				Archived:    activityArchived,
+				Concurrent:  activityConcurrent,
 			},
 		}
 
@@ -469,5 +1480,136 @@ func (repo *SqliteRepository) activityLogStartedAt(instant time.Time) ([]core.Ac
 		return nil, err
 	}
 
+	activityLogs, err = loadPauses(q, activityLogs, condition, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadLogTags(q, activityLogs, condition, args...)
+}
+
+// loadLogTags fills the tags of the logs, and of their activities, which were fetched with the given condition.
+func loadLogTags(q queryer, activityLogs []core.ActivityLog, condition string, args ...interface{}) ([]core.ActivityLog, error) {
+	if len(activityLogs) == 0 {
+		return activityLogs, nil
+	}
+
+	tags, err := queryTags(q, `
+		SELECT activity_log_tags.activity_log_id, tags.name
+		FROM activity_log_tags, tags, activity_logs, activities
+		WHERE tags.id = activity_log_tags.tag_id
+			AND activity_logs.id = activity_log_tags.activity_log_id
+			AND activities.id = activity_logs.activity_id
+			AND `+condition+`
+		ORDER BY tags.name
+	`, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	activityTags, err := activityTagsById(q)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range activityLogs {
+		activityLogs[i].Tags = tags[activityLogs[i].Id]
+		activityLogs[i].Activity.Tags = activityTags[activityLogs[i].Activity.Id]
+	}
+
+	return activityLogs, nil
+}
+
+// loadPauses fills the pauses of the logs, which were fetched with the given condition.
+func loadPauses(q queryer, activityLogs []core.ActivityLog, condition string, args ...interface{}) ([]core.ActivityLog, error) {
+	if len(activityLogs) == 0 {
+		return activityLogs, nil
+	}
+
+	query := `
+		SELECT activity_log_pauses.id,
+			   activity_log_pauses.activity_log_id,
+			   activity_log_pauses.paused_at,
+			   activity_log_pauses.paused_at_offset,
+			   activity_log_pauses.resumed_at,
+			   activity_log_pauses.resumed_at_offset
+		FROM activity_log_pauses, activity_logs, activities
+		WHERE activity_log_pauses.activity_log_id = activity_logs.id
+			AND activities.id = activity_logs.activity_id
+			AND ` + condition + `
+		ORDER BY activity_log_pauses.paused_at
+	`
+
+	rows, err := q.Query(query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	pauses := make(map[int][]core.Pause)
+	for rows.Next() {
+		var pauseId int
+		var logId int
+		var pausedAt *time.Time
+		var pausedAtOffset int
+		var resumedAt *time.Time
+		var resumedAtOffset sql.NullInt64
+		err = rows.Scan(&pauseId, &logId, &pausedAt, &pausedAtOffset, &resumedAt, &resumedAtOffset)
+		if err != nil {
+			return nil, err
+		}
+
+		pauses[logId] = append(pauses[logId], core.Pause{
+			Id:        pauseId,
+			PausedAt:  loadedTime(pausedAt, pausedAtOffset),
+			ResumedAt: loadedTime(resumedAt, int(resumedAtOffset.Int64)),
+		})
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range activityLogs {
+		activityLogs[i].Pauses = pauses[activityLogs[i].Id]
+	}
+
 	return activityLogs, nil
 }
+
+// inPeriodCondition selects the logs taking place between two stored instants, given as the end and twice the start.
+// Logs running past their planned end are considered stopped at that end.
+const inPeriodCondition = `activity_logs.started_at < ? AND (activity_logs.stopped_at > ? OR
+	(activity_logs.stopped_at IS NULL AND (activity_logs.planned_end IS NULL OR activity_logs.planned_end > ?)))`
+
+// periodBounds returns the instants, in the stored format, the period starts and ends at,
+// its days being calendar days of the given time zone.
+func periodBounds(period core.Period, loc *time.Location) (string, string) {
+	bounds := period.Anchored(loc).Interval()
+	from, _ := storedTime(bounds.Start)
+	to, _ := storedTime(bounds.End)
+	return from, to
+}
+
+// storedTime returns the representation of an instant stored in the database: the time in UTC, and the
+// offset in seconds of the time zone it was recorded in.
+func storedTime(instant time.Time) (string, int) {
+	_, offset := instant.Zone()
+	return utils.TimeToStandardDateTimeFormat(instant.UTC()), offset
+}
+
+// loadedTime restores an instant read from the database in the time zone offset it was recorded in.
+func loadedTime(instant *time.Time, offset int) *time.Time {
+	if instant == nil {
+		return nil
+	}
+
+	restored := instant.In(time.FixedZone("", offset))
+	return &restored
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/luispcosta/go-tt/config"
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/persistence/repositorytest"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+func TestSqliteRepository(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T, clock utils.Clock, conf config.Config) core.ActivityRepository {
+		return newTestSqliteRepository(t, clock, conf)
+	})
+}
+
+// newTestSqliteRepository creates a repository backed by a database file in a temporary folder.
+func newTestSqliteRepository(t *testing.T, clock utils.Clock, conf config.Config) *SqliteRepository {
+	repo, err := NewSqliteRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.Clock = clock
+
+	conf.UserDataLocation = fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator))
+	err = repo.Initialize(conf)
+	if err != nil {
+		t.Fatalf("Should have initialized the database: %v", err)
+	}
+	return repo
+}
+
+func TestSqliteRepositoryKeepsRevertedMigrationsWhenSkippingMigrations(t *testing.T) {
+	conf := config.NewConfig()
+	conf.UserDataLocation = fmt.Sprintf("%s%s", t.TempDir(), string(os.PathSeparator))
+	latest, err := LatestSchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, _ := NewSqliteRepository()
+	err = repo.Initialize(conf)
+	if err == nil {
+		err = repo.MigrateDown(1)
+	}
+	if err != nil {
+		t.Fatalf("Should have reverted the last migration: %v", err)
+	}
+	repo.Shutdown()
+
+	conf.SkipMigrations = true
+	repo, _ = NewSqliteRepository()
+	err = repo.Initialize(conf)
+	if err != nil {
+		t.Fatalf("Should have opened the database: %v", err)
+	}
+	defer repo.Shutdown()
+
+	version, _, err := repo.SchemaVersion()
+	if err != nil || version != latest-1 {
+		t.Errorf("Should have kept the schema at version %d, got %d (%v)", latest-1, version, err)
+	}
+}
+
+func TestSqliteRepositoryRefusesNewerSchema(t *testing.T) {
+	repo := newTestSqliteRepository(t, utils.NewLiveClock(), config.NewConfig())
+	defer repo.Shutdown()
+
+	_, err := repo.db.Exec(fmt.Sprintf("UPDATE %s SET version = version + 1", SchemaVersionTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = repo.Migrate()
+	if err == nil {
+		t.Error("Should have refused to work with a schema newer than the binary")
+	}
+}
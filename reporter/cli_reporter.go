@@ -2,10 +2,10 @@ package reporter
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/luispcosta/go-tt/core"
-	"github.com/luispcosta/go-tt/utils"
 )
 
 // CliReporter is an activity reporter that presents activity information in the standard out
@@ -14,6 +14,10 @@ type CliReporter struct {
 	Repo           core.ActivityRepository
 	Printer        func(...interface{}) (int, error)
 	DurationFormat core.DurationFormat
+	Filter         core.LogFilter
+	TagTotals      bool
+	Depth          int
+	MaxSession     time.Duration
 }
 
 // NewCliReporter creates a new CLI reporter
@@ -45,36 +49,82 @@ func (reporter *CliReporter) SetDurationFormat(f core.DurationFormat) {
 	reporter.DurationFormat = f
 }
 
+// SetFilter sets the filter selecting the sessions in the report
+func (reporter *CliReporter) SetFilter(filter core.LogFilter) {
+	reporter.Filter = filter
+}
+
+// SetTagTotals sets whether the report includes the total duration per tag
+func (reporter *CliReporter) SetTagTotals(show bool) {
+	reporter.TagTotals = show
+}
+
+// SetDepth sets the depth at which the tree of activities is collapsed, zero meaning the whole tree
+func (reporter *CliReporter) SetDepth(depth int) {
+	reporter.Depth = depth
+}
+
+// SetMaxSession sets the duration above which sessions are flagged as too long, zero meaning no limit
+func (reporter *CliReporter) SetMaxSession(maxSession time.Duration) {
+	reporter.MaxSession = maxSession
+}
+
 // ProduceReport creates a new cli report in the given period
 func (reporter *CliReporter) ProduceReport() error {
-	logs, err := reporter.Repo.LogsForPeriod(reporter.Period)
+	logs, err := reporter.Repo.LogsForPeriod(reporter.Period, reporter.Filter)
 	if err != nil {
 		return err
 	}
 
-	reporter.Period.ForEachDay(func(d time.Time) error {
-		date := d.Format(utils.DateFormat)
+	for _, day := range reporter.Period.SplitByDay() {
+		date := day.StartDateDay()
 		header := fmt.Sprintf("Day %s: \n", date)
+		if day.Exact {
+			header = fmt.Sprintf("Day %s (%s - %s): \n", date, day.Sd.Format(clockFormat), day.Ed.Format(clockFormat))
+		}
 		activityLogs := logs[date]
 
 		var content string
 		if len(activityLogs) == 0 {
 			content = "  No activities found for this day"
 		} else {
-			for i := range activityLogs {
-				entry := activityLogs[i]
+			core.WalkActivityTree(core.BuildActivityTree(activityLogs, reporter.Depth), func(node *core.ActivityTreeNode, level int) {
+				indent := strings.Repeat("  ", level+1)
+				entry := node.Own
 
-				content += fmt.Sprintf("  Activity %s", entry.Activity.Name)
-				content += fmt.Sprintf(" %v", reporter.DurationFormat.Format(entry.Duration))
+				content += fmt.Sprintf("%sActivity %s", indent, node.ShortName())
+				content += fmt.Sprintf(" %v", reporter.DurationFormat.Format(node.Duration))
+				if node.HasSubActivityTime() {
+					content += fmt.Sprintf(" (own %v)", reporter.DurationFormat.Format(entry.Duration))
+				}
+				if tags := entry.Tags(); len(tags) > 0 {
+					content += fmt.Sprintf(" (%s)", core.FormatTags(tags))
+				}
+				if hasLongSession(node, reporter.MaxSession) {
+					content += fmt.Sprintf(" (!) session of %v", reporter.DurationFormat.Format(node.LongestSession))
+				}
 				content += "\n"
-			}
+				for _, note := range entry.Notes {
+					content += fmt.Sprintf("%s  - %s\n", indent, note)
+				}
+			})
 		}
 		reporter.Printer(header)
 		reporter.Printer(content)
 		reporter.Printer("\n\n")
+	}
 
-		return nil
-	})
+	if reporter.TagTotals {
+		totals := core.TagTotals(logs)
+		content := "Tags: \n"
+		if len(totals) == 0 {
+			content += "  No tags found for this period\n"
+		}
+		for _, tag := range sortedTags(totals) {
+			content += fmt.Sprintf("  %s%s %v\n", core.TagPrefix, tag, reporter.DurationFormat.Format(totals[tag]))
+		}
+		reporter.Printer(content)
+	}
 
 	return nil
 }
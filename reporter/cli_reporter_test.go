@@ -0,0 +1,147 @@
+package reporter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/persistence"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+func TestCliReporterPrintsDurationsAndNotes(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "coding"})
+	coding, _ := repo.Find("coding")
+	repo.Start(*coding, core.StartOptions{Note: "refactor parser"})
+	clock.SetNow(clock.Now().Add(time.Hour))
+	repo.Stop(*coding, core.StopOptions{})
+
+	var output strings.Builder
+	reporter := NewCustomCLIReporter(func(a ...interface{}) (int, error) {
+		return fmt.Fprint(&output, a...)
+	})
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	err := reporter.ProduceReport()
+	if err != nil {
+		t.Fatalf("Should have produced the report: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "Activity coding 60") {
+		t.Errorf("Report should contain the activity duration, got:\n%s", output.String())
+	}
+
+	if !strings.Contains(output.String(), "- refactor parser") {
+		t.Errorf("Report should contain the session notes, got:\n%s", output.String())
+	}
+}
+
+func TestCliReporterPrintsTreeOfActivities(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "work"})
+	repo.Add(core.Activity{Name: "work/backend"})
+	for _, name := range []string{"work", "work/backend"} {
+		activity, _ := repo.Find(name)
+		repo.Start(*activity, core.StartOptions{})
+		clock.SetNow(clock.Now().Add(30 * time.Minute))
+		repo.Stop(*activity, core.StopOptions{})
+	}
+
+	var output strings.Builder
+	reporter := NewCustomCLIReporter(func(a ...interface{}) (int, error) {
+		return fmt.Fprint(&output, a...)
+	})
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	reporter.ProduceReport()
+
+	expected := "  Activity work 60 (own 30)\n    Activity backend 30\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("Report should contain the tree of activities, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	reporter.SetDepth(1)
+	reporter.ProduceReport()
+
+	if strings.Contains(output.String(), "backend") {
+		t.Errorf("Report collapsed at depth 1 should not contain sub-activities, got:\n%s", output.String())
+	}
+}
+
+func TestCliReporterClipsSessionsToExactPeriod(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	start := time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local)
+	clock := utils.NewMockedClock(start)
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "coding"})
+	coding, _ := repo.Find("coding")
+	repo.Start(*coding, core.StartOptions{})
+	clock.SetNow(start.Add(2 * time.Hour))
+	repo.Stop(*coding, core.StopOptions{})
+
+	var output strings.Builder
+	reporter := NewCustomCLIReporter(func(a ...interface{}) (int, error) {
+		return fmt.Fprint(&output, a...)
+	})
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	reporter.Initialize(repo, core.Period{Sd: start.Add(90 * time.Minute), Ed: start.Add(3 * time.Hour), Exact: true})
+
+	err := reporter.ProduceReport()
+	if err != nil {
+		t.Fatalf("Should have produced the report: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "Day 2020-10-10 (10:30 - 12:00)") || !strings.Contains(output.String(), "Activity coding 30") {
+		t.Errorf("Report should only count the time inside the period, got:\n%s", output.String())
+	}
+}
+
+func TestCliReporterFlagsSessionsLongerThanMaximum(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "coding"})
+	repo.Add(core.Activity{Name: "reading"})
+	coding, _ := repo.Find("coding")
+	repo.Start(*coding, core.StartOptions{})
+	clock.SetNow(clock.Now().Add(3 * time.Hour))
+	repo.Stop(*coding, core.StopOptions{})
+	reading, _ := repo.Find("reading")
+	repo.Start(*reading, core.StartOptions{})
+	clock.SetNow(clock.Now().Add(time.Hour))
+	repo.Stop(*reading, core.StopOptions{})
+
+	var output strings.Builder
+	reporter := NewCustomCLIReporter(func(a ...interface{}) (int, error) {
+		return fmt.Fprint(&output, a...)
+	})
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	reporter.SetMaxSession(2 * time.Hour)
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	err := reporter.ProduceReport()
+	if err != nil {
+		t.Fatalf("Should have produced the report: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "Activity coding 180 (!) session of 180") {
+		t.Errorf("Report should flag the long session, got:\n%s", output.String())
+	}
+
+	if strings.Contains(output.String(), "Activity reading 60 (!)") {
+		t.Errorf("Report should not flag sessions shorter than the maximum, got:\n%s", output.String())
+	}
+}
@@ -3,17 +3,29 @@ package reporter
 import (
 	"encoding/csv"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/luispcosta/go-tt/core"
 	"github.com/luispcosta/go-tt/utils"
 )
 
+// csvHeader names the columns of the activity rows. The duration is the time spent on the activity itself, and the
+// total duration adds the time spent on its sub-activities.
+var csvHeader = []string{"date", "activity", "duration", "total_duration", "notes", "tags", "long_session"}
+
+// csvTagTotalsHeader names the columns of the tag totals, which are written to a file of their own
+var csvTagTotalsHeader = []string{"tag", "duration"}
+
 // CsvReporter is an activity reporter that exports activity information to a csv file.
 type CsvReporter struct {
 	Period         core.Period
 	Repo           core.ActivityRepository
 	DurationFormat core.DurationFormat
+	Filter         core.LogFilter
+	TagTotals      bool
+	Depth          int
+	MaxSession     time.Duration
 	Clock          utils.Clock
 }
 
@@ -38,46 +50,83 @@ func (reporter *CsvReporter) SetDurationFormat(f core.DurationFormat) {
 	reporter.DurationFormat = f
 }
 
-// ProduceReport creates a new CSV report in the given period
-func (reporter *CsvReporter) ProduceReport() error {
-	logs, err := reporter.Repo.LogsForPeriod(reporter.Period)
-	if err != nil {
-		return err
-	}
+// SetFilter sets the filter selecting the sessions in the report
+func (reporter *CsvReporter) SetFilter(filter core.LogFilter) {
+	reporter.Filter = filter
+}
 
-	fileName := fmt.Sprintf("report_%s_%s_%v.csv", reporter.Period.Sd.Format("2006_01_02"), reporter.Period.Ed.Format("2006_01_02"), reporter.Clock.Now().Unix())
-	file, err := utils.NewFile(fileName)
-	defer func() error {
-		err := file.Close()
-		if err != nil {
-			return err
-		}
-		return nil
-	}()
+// SetTagTotals sets whether the report includes the total duration per tag
+func (reporter *CsvReporter) SetTagTotals(show bool) {
+	reporter.TagTotals = show
+}
+
+// SetDepth sets the depth at which the tree of activities is collapsed, zero meaning the whole tree
+func (reporter *CsvReporter) SetDepth(depth int) {
+	reporter.Depth = depth
+}
 
+// SetMaxSession sets the duration above which sessions are flagged as too long, zero meaning no limit
+func (reporter *CsvReporter) SetMaxSession(maxSession time.Duration) {
+	reporter.MaxSession = maxSession
+}
+
+// ProduceReport creates a new CSV report in the given period
+func (reporter *CsvReporter) ProduceReport() error {
+	logs, err := reporter.Repo.LogsForPeriod(reporter.Period, reporter.Filter)
 	if err != nil {
 		return err
 	}
-	w := csv.NewWriter(file)
-	defer w.Flush()
 
-	reporter.Period.ForEachDay(func(d time.Time) error {
-		date := d.Format(utils.DateFormat)
+	baseName := fmt.Sprintf("report_%s_%s_%v", reporter.Period.Sd.Format("2006_01_02"), reporter.Period.Ed.Format("2006_01_02"), reporter.Clock.Now().Unix())
+	var rows [][]string
+	for _, day := range reporter.Period.SplitByDay() {
+		date := day.StartDateDay()
 		activityLogs := logs[date]
 
 		if len(activityLogs) != 0 {
-			for i := range activityLogs {
-				entry := activityLogs[i]
-				row := []string{date, entry.Activity.Name, reporter.DurationFormat.Format(entry.Duration)}
-				err := w.Write(row)
-				if err != nil {
-					return err
+			core.WalkActivityTree(core.BuildActivityTree(activityLogs, reporter.Depth), func(node *core.ActivityTreeNode, level int) {
+				entry := node.Own
+				row := []string{
+					date,
+					node.Name,
+					reporter.DurationFormat.Format(entry.Duration),
+					reporter.DurationFormat.Format(node.Duration),
+					strings.Join(entry.Notes, "; "),
+					core.FormatTags(entry.Tags()),
+					"",
 				}
-			}
+				if hasLongSession(node, reporter.MaxSession) {
+					row[6] = reporter.DurationFormat.Format(node.LongestSession)
+				}
+				rows = append(rows, row)
+			})
 		}
+	}
+
+	err = writeCsv(baseName+".csv", csvHeader, rows)
+	if err != nil || !reporter.TagTotals {
+		return err
+	}
 
-		return nil
-	})
+	totals := core.TagTotals(logs)
+	var tagRows [][]string
+	for _, tag := range sortedTags(totals) {
+		tagRows = append(tagRows, []string{core.TagPrefix + tag, reporter.DurationFormat.Format(totals[tag])})
+	}
+	return writeCsv(baseName+"_tags.csv", csvTagTotalsHeader, tagRows)
+}
 
-	return nil
+// writeCsv writes the header and the rows to a new file
+func writeCsv(fileName string, header []string, rows [][]string) error {
+	file, err := utils.NewFile(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = csv.NewWriter(file).WriteAll(append([][]string{header}, rows...))
+	if err != nil {
+		return err
+	}
+	return file.Close()
 }
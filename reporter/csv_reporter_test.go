@@ -0,0 +1,112 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/persistence"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+func TestCsvReporterWritesOneLayoutWithAHeader(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "work"})
+	repo.Add(core.Activity{Name: "work/backend"})
+	for _, name := range []string{"work", "work/backend"} {
+		activity, _ := repo.Find(name)
+		repo.Start(*activity, core.StartOptions{Note: "on " + name})
+		clock.SetNow(clock.Now().Add(30 * time.Minute))
+		repo.Stop(*activity, core.StopOptions{})
+	}
+
+	reporter := NewCsvReporter()
+	reporter.Clock = clock
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	rows, tagRows := produceCsvReport(t, reporter)
+	expected := [][]string{
+		csvHeader,
+		{"2020-10-10", "work", "30", "60", "on work", "", ""},
+		{"2020-10-10", "work/backend", "30", "30", "on work/backend", "", ""},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Report should have a header and one row per activity, got:\n%q", rows)
+	}
+
+	if tagRows != nil {
+		t.Errorf("Should not have written tag totals without --tag-totals, got:\n%q", tagRows)
+	}
+}
+
+func TestCsvReporterWritesTagTotalsToTheirOwnFile(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "coding"})
+	coding, _ := repo.Find("coding")
+	repo.Start(*coding, core.StartOptions{Tags: []string{"billable"}})
+	clock.SetNow(clock.Now().Add(time.Hour))
+	repo.Stop(*coding, core.StopOptions{})
+
+	reporter := NewCsvReporter()
+	reporter.Clock = clock
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	reporter.SetTagTotals(true)
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	rows, tagRows := produceCsvReport(t, reporter)
+	expected := [][]string{csvHeader, {"2020-10-10", "coding", "60", "60", "", "+billable", ""}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("Report should only have the activity rows, got:\n%q", rows)
+	}
+
+	expected = [][]string{csvTagTotalsHeader, {"+billable", "60"}}
+	if !reflect.DeepEqual(tagRows, expected) {
+		t.Errorf("Tag totals should have their own file with a header, got:\n%q", tagRows)
+	}
+}
+
+// produceCsvReport produces the report in a temporary directory and returns the rows of the report file and of the
+// tag totals file, nil if there is none
+func produceCsvReport(t *testing.T, reporter *CsvReporter) ([][]string, [][]string) {
+	dir, _ := os.Getwd()
+	defer os.Chdir(dir)
+	os.Chdir(t.TempDir())
+
+	err := reporter.ProduceReport()
+	if err != nil {
+		t.Fatalf("Should have produced the report: %v", err)
+	}
+
+	baseName := fmt.Sprintf("report_2020_10_10_2020_10_10_%v", reporter.Clock.Now().Unix())
+	rows := readCsvFile(t, baseName+".csv")
+	if _, err := os.Stat(baseName + "_tags.csv"); err != nil {
+		return rows, nil
+	}
+	return rows, readCsvFile(t, baseName+"_tags.csv")
+}
+
+// readCsvFile returns the rows of a csv file
+func readCsvFile(t *testing.T, fileName string) [][]string {
+	file, err := os.Open(fileName)
+	if err != nil {
+		t.Fatalf("Should have written %s: %v", fileName, err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Should have written a valid csv file: %v", err)
+	}
+	return rows
+}
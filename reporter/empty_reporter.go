@@ -1,6 +1,8 @@
 package reporter
 
 import (
+	"time"
+
 	"github.com/luispcosta/go-tt/core"
 	"github.com/luispcosta/go-tt/utils"
 )
@@ -27,3 +29,19 @@ func (reporter *EmptyReporter) ProduceReport() error {
 // SetDurationFormat no-op
 func (reporter *EmptyReporter) SetDurationFormat(f core.DurationFormat) {
 }
+
+// SetFilter no-op
+func (reporter *EmptyReporter) SetFilter(filter core.LogFilter) {
+}
+
+// SetTagTotals no-op
+func (reporter *EmptyReporter) SetTagTotals(show bool) {
+}
+
+// SetDepth no-op
+func (reporter *EmptyReporter) SetDepth(depth int) {
+}
+
+// SetMaxSession no-op
+func (reporter *EmptyReporter) SetMaxSession(maxSession time.Duration) {
+}
@@ -14,6 +14,10 @@ type JsonReporter struct {
 	Period         core.Period
 	Repo           core.ActivityRepository
 	DurationFormat core.DurationFormat
+	Filter         core.LogFilter
+	TagTotals      bool
+	Depth          int
+	MaxSession     time.Duration
 	Clock          utils.Clock
 }
 
@@ -38,49 +42,115 @@ func (reporter *JsonReporter) SetDurationFormat(f core.DurationFormat) {
 	reporter.DurationFormat = f
 }
 
-// Struct example:
+// Struct example. The duration of an activity includes the one of its sub-activities, while own_duration does not.
+// long_session is only present when the activity, or one of its sub-activities, has a session above the maximum duration:
 /*
 	{
 		'2020-10-10: {
-			'act1': "123",
-			'act2': "12",
-			'act3': "121",
-			'act4': "23",
-			'act5': "10",
+			'act1': {"duration": "123", "own_duration": "123", "notes": ["refactor parser"], "tags": ["billable"]},
+			'act2': {"duration": "12", "own_duration": "2", "notes": [], "tags": [], "children": {
+				'sub': {"duration": "10", "own_duration": "10", "notes": [], "tags": []}
+			}},
+			...
 		},
 		...
 	}
 */
-type jsonData map[string]map[string]string
+type jsonData map[string]interface{}
+
+type jsonActivityData struct {
+	Duration    string                      `json:"duration"`
+	OwnDuration string                      `json:"own_duration"`
+	Notes       []string                    `json:"notes"`
+	Tags        []string                    `json:"tags"`
+	LongSession string                      `json:"long_session,omitempty"`
+	Children    map[string]jsonActivityData `json:"children,omitempty"`
+}
+
+// Tag totals example, written to a file of their own when requested:
+/*
+	{
+		'billable': {"duration": "123"},
+		...
+	}
+*/
+type jsonTagData struct {
+	Duration string `json:"duration"`
+}
+
+// SetFilter sets the filter selecting the sessions in the report
+func (reporter *JsonReporter) SetFilter(filter core.LogFilter) {
+	reporter.Filter = filter
+}
+
+// SetTagTotals sets whether the report includes the total duration per tag
+func (reporter *JsonReporter) SetTagTotals(show bool) {
+	reporter.TagTotals = show
+}
+
+// SetDepth sets the depth at which the tree of activities is collapsed, zero meaning the whole tree
+func (reporter *JsonReporter) SetDepth(depth int) {
+	reporter.Depth = depth
+}
+
+// SetMaxSession sets the duration above which sessions are flagged as too long, zero meaning no limit
+func (reporter *JsonReporter) SetMaxSession(maxSession time.Duration) {
+	reporter.MaxSession = maxSession
+}
 
 // ProduceReport creates a new json report in the given period
 func (reporter *JsonReporter) ProduceReport() error {
-	logs, err := reporter.Repo.LogsForPeriod(reporter.Period)
+	logs, err := reporter.Repo.LogsForPeriod(reporter.Period, reporter.Filter)
 	if err != nil {
 		return err
 	}
 
 	data := make(jsonData)
 
-	reporter.Period.ForEachDay(func(d time.Time) error {
-		date := d.Format(utils.DateFormat)
+	for _, day := range reporter.Period.SplitByDay() {
+		date := day.StartDateDay()
 		activityLogs := logs[date]
 
 		if len(activityLogs) != 0 {
-			actData := make(map[string]string)
-			for i := range activityLogs {
-				entry := activityLogs[i]
-				actData[entry.Activity.Name] = reporter.DurationFormat.Format(entry.Duration)
-			}
-			data[date] = actData
+			data[date] = reporter.activityTreeData(core.BuildActivityTree(activityLogs, reporter.Depth))
 		}
+	}
 
-		return nil
-	})
-
-	fileName := fmt.Sprintf("report_%s_%s_%v.json", reporter.Period.Sd.Format("2006_01_02"), reporter.Period.Ed.Format("2006_01_02"), reporter.Clock.Now().Unix())
+	baseName := fmt.Sprintf("report_%s_%s_%v", reporter.Period.Sd.Format("2006_01_02"), reporter.Period.Ed.Format("2006_01_02"), reporter.Clock.Now().Unix())
 	fileData, _ := json.MarshalIndent(data, "", " ")
-	utils.WriteToFile(fileName, fileData)
+	err = utils.WriteToFile(baseName+".json", fileData)
+	if err != nil || !reporter.TagTotals {
+		return err
+	}
 
-	return nil
+	tagData := make(map[string]jsonTagData)
+	for tag, duration := range core.TagTotals(logs) {
+		tagData[tag] = jsonTagData{Duration: reporter.DurationFormat.Format(duration)}
+	}
+	fileData, _ = json.MarshalIndent(tagData, "", " ")
+	return utils.WriteToFile(baseName+"_tags.json", fileData)
+}
+
+// activityTreeData converts the nodes of the tree of activities, keyed by their name without the name of their parent
+func (reporter *JsonReporter) activityTreeData(nodes []*core.ActivityTreeNode) map[string]jsonActivityData {
+	actData := make(map[string]jsonActivityData)
+	for _, node := range nodes {
+		entry := node.Own
+		notes := entry.Notes
+		if notes == nil {
+			notes = []string{}
+		}
+		data := jsonActivityData{
+			Duration:    reporter.DurationFormat.Format(node.Duration),
+			OwnDuration: reporter.DurationFormat.Format(entry.Duration),
+			Notes:       notes,
+			Tags:        entry.Tags(),
+			Children:    reporter.activityTreeData(node.Children),
+		}
+		if hasLongSession(node, reporter.MaxSession) {
+			data.LongSession = reporter.DurationFormat.Format(node.LongestSession)
+		}
+		actData[node.ShortName()] = data
+	}
+	return actData
 }
@@ -0,0 +1,65 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/luispcosta/go-tt/core"
+	"github.com/luispcosta/go-tt/persistence"
+	"github.com/luispcosta/go-tt/utils"
+)
+
+func TestJsonReporterWritesTagTotalsToTheirOwnFile(t *testing.T) {
+	repo := persistence.NewMemoryRepository()
+	clock := utils.NewMockedClock(time.Date(2020, 10, 10, 9, 0, 0, 0, time.Local))
+	repo.Clock = clock
+	repo.Add(core.Activity{Name: "coding"})
+	coding, _ := repo.Find("coding")
+	repo.Start(*coding, core.StartOptions{Tags: []string{"billable"}})
+	clock.SetNow(clock.Now().Add(time.Hour))
+	repo.Stop(*coding, core.StopOptions{})
+
+	reporter := NewJsonReporter()
+	reporter.Clock = clock
+	reporter.SetDurationFormat(core.MinutesDurationFormat{})
+	reporter.SetTagTotals(true)
+	period, _ := core.PeriodFromDateStrings("2020-10-10", "2020-10-10")
+	reporter.Initialize(repo, period)
+
+	dir, _ := os.Getwd()
+	defer os.Chdir(dir)
+	os.Chdir(t.TempDir())
+	err := reporter.ProduceReport()
+	if err != nil {
+		t.Fatalf("Should have produced the report: %v", err)
+	}
+
+	baseName := fmt.Sprintf("report_2020_10_10_2020_10_10_%v", clock.Now().Unix())
+	var days map[string]map[string]jsonActivityData
+	readJsonFile(t, baseName+".json", &days)
+	if len(days) != 1 || days["2020-10-10"]["coding"].Duration != "60" {
+		t.Errorf("Report should only have the days, got %+v", days)
+	}
+
+	var tags map[string]jsonTagData
+	readJsonFile(t, baseName+"_tags.json", &tags)
+	if len(tags) != 1 || tags["billable"].Duration != "60" {
+		t.Errorf("Tag totals should have their own file, got %+v", tags)
+	}
+}
+
+// readJsonFile decodes a json file into the value
+func readJsonFile(t *testing.T, fileName string, value interface{}) {
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Should have written %s: %v", fileName, err)
+	}
+
+	err = json.Unmarshal(content, value)
+	if err != nil {
+		t.Fatalf("Should have written valid json to %s: %v", fileName, err)
+	}
+}
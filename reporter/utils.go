@@ -1,7 +1,9 @@
 package reporter
 
 import (
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/luispcosta/go-tt/core"
 )
@@ -10,6 +12,14 @@ const jsonFormat = "json"
 const csvFormat = "csv"
 const cliFormat = "cli"
 
+// hasLongSession returns true if the longest session of the node is above the maximum duration of a session, if any
+func hasLongSession(node *core.ActivityTreeNode, maxSession time.Duration) bool {
+	return maxSession > 0 && time.Duration(node.LongestSession)*time.Second > maxSession
+}
+
+// clockFormat is the format of the times of day shown in reports
+const clockFormat = "15:04"
+
 // AllowedFormats creates a map with the allowed report formats and their implementations
 func AllowedFormats() map[string]core.Reporter {
 	allowedFormats := make(map[string]core.Reporter)
@@ -33,3 +43,13 @@ func IsAllowedFormat(format string) bool {
 func CreateReporter(format string) core.Reporter {
 	return AllowedFormats()[strings.ToLower(format)]
 }
+
+// sortedTags returns the tags of the totals, sorted by name
+func sortedTags(totals map[string]int) []string {
+	tags := make([]string, 0, len(totals))
+	for tag := range totals {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -3,6 +3,7 @@ package utils
 import (
 	"math"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -44,6 +45,16 @@ func SecondsToHuman(input int) (result string) {
 	return
 }
 
+// ParseWeekday returns the day of the week with the given name, as in "monday", ignoring case
+func ParseWeekday(name string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.EqualFold(weekday.String(), name) {
+			return weekday, true
+		}
+	}
+	return time.Sunday, false
+}
+
 func TimeToStandardDateTimeFormat(time time.Time) string {
 	return time.Format("2006-01-02 15:04:05")
 }